@@ -3,33 +3,12 @@ package main
 import (
 	"flag"
 	"fmt"
-	"go/build"
 	"os"
-	"os/exec"
-	"os/user"
-	"path/filepath"
-	"strings"
-)
-
-const dockerImage = "lucor/fyne-cross"
-
-// targetWithBuildOpts represents the list of supported GOOS/GOARCH with the relative
-// options to build
-var targetWithBuildOpts = map[string][]string{
-	"darwin/amd64":  []string{"GOOS=darwin", "GOARCH=amd64", "CC=o32-clang"},
-	"darwin/386":    []string{"GOOS=darwin", "GOARCH=386", "CC=o32-clang"},
-	"linux/amd64":   []string{"GOOS=linux", "GOARCH=amd64", "CC=gcc"},
-	"linux/386":     []string{"GOOS=linux", "GOARCH=386", "CC=gcc"},
-	"windows/amd64": []string{"GOOS=windows", "GOARCH=amd64", "CC=x86_64-w64-mingw32-gcc"},
-	"windows/386":   []string{"GOOS=windows", "GOARCH=386", "CC=x86_64-w64-mingw32-gcc"},
-}
+	"sort"
+	"time"
 
-// targetLdflags represents the list of default ldflags to pass on build
-// for a specified GOOS/GOARCH
-var targetLdflags = map[string]string{
-	"windows/amd64": "-H windowsgui",
-	"windows/386":   "-H windowsgui",
-}
+	"github.com/lucor/fyne-cross/pkg/build"
+)
 
 var (
 	// targetList represents a list of target to build on separated by comma
@@ -46,28 +25,172 @@ var (
 	verbose bool
 	// ldflags represents the flags to pass to the external linker
 	ldflags string
+	// buildMode represents the go build mode
+	buildMode string
+	// harden represents the setting to build the linux targets with the hardening flags
+	harden bool
+	// windowsConsole represents the setting to build the windows targets as console applications
+	windowsConsole bool
+	// cgoCFLAGS represents the per target flags passed to the C compiler
+	cgoCFLAGS stringSliceFlag
+	// cgoLDFLAGS represents the per target flags passed to the C linker
+	cgoLDFLAGS stringSliceFlag
+	// pkgConfigPath represents the per target pkg-config folders
+	pkgConfigPath stringSliceFlag
+	// goarm represents the ARM architecture version of the arm targets
+	goarm string
+	// goamd64 represents the microarchitecture level of the amd64 targets
+	goamd64 string
+	// sbomEnabled represents the setting to generate a SBOM for each target
+	sbomEnabled bool
+	// targetSubdir represents the setting to write the artifacts into per target subdirectories
+	targetSubdir bool
+	// bundleDir represents the assets directory to bundle via fyne bundle before compiling
+	bundleDir string
+	// bundlePkg represents the package name of the bundled go file
+	bundlePkg string
+	// bundleOutput represents the bundled go file
+	bundleOutput string
+	// icon represents the PNG icon used to generate the platform specific icons
+	icon string
+	// force represents the setting to force rebuilding of packages that are already up-to-date
+	force bool
+	// ccacheEnabled represents the setting to compile the C code via ccache
+	ccacheEnabled bool
+	// selinuxLabel represents the SELinux label option for the volume mounts
+	selinuxLabel string
+	// httpProxy represents the proxy for HTTP requests
+	httpProxy string
+	// httpsProxy represents the proxy for HTTPS requests
+	httpsProxy string
+	// noProxy represents the list of hosts excluded from proxying
+	noProxy string
+	// caCerts represents the custom CA certificates to add to the container trust store
+	caCerts stringSliceFlag
+	// volumes represents the additional volumes mounted into the container
+	volumes stringSliceFlag
+	// containerUser represents the user running the commands into the container
+	containerUser string
+	// containerGroup represents the group running the commands into the container
+	containerGroup string
+	// keepGoing represents the setting to continue building the remaining targets on failure
+	keepGoing bool
+	// appVersion represents the version of the build
+	appVersion string
+	// deltaFrom represents the build folder of the previous release to generate the binary diffs from
+	deltaFrom string
+	// packagerNames represents the packagers to run on the built artifacts
+	packagerNames stringSliceFlag
+	// publisherNames represents the publishers to run on the artifacts
+	publisherNames stringSliceFlag
+	// image represents the docker image used to build
+	image string
+	// envVars represents the env variables to export into the container
+	envVars stringSliceFlag
+	// envFile represents the env file whose variables are exported into the container
+	envFile string
+	// goVersion represents the Go version of the toolchain to download
+	goVersion string
+	// retries represents the number of retries of the network dependent steps
+	retries int
+	// cpus represents the number of CPUs available to the containers
+	cpus string
+	// memory represents the memory limit of the containers
+	memory string
+	// network represents the network of the build container
+	network string
+	// aptPackages represents the extra system packages to install into the image
+	aptPackages stringSliceFlag
+	// timeout represents the timeout of each build step
+	timeout time.Duration
+	// noColor represents the setting to disable the colors of the target prefixes of the build output
+	noColor bool
+	// githubOutput represents the setting to integrate with the GitHub Actions workflow log and step outputs
+	githubOutput bool
 )
 
 // builder is the command implementing the fyne app command interface
 type builder struct{}
 
 func (b *builder) addFlags() {
-	defaultTarget := strings.Join([]string{build.Default.GOOS, build.Default.GOARCH}, "/")
-	flag.StringVar(&targetList, "targets", defaultTarget, fmt.Sprintf("The list of targets to build separated by comma. Default to current GOOS/GOARCH %s", defaultTarget))
+	addTargetsFlag("build")
+	addCommonFlags()
 	flag.StringVar(&output, "output", "", "The named output file. Default to package name")
+	flag.StringVar(&ldflags, "ldflags", "", "flags to pass to the external linker")
+	flag.StringVar(&buildMode, "buildmode", "exe", "The go build mode: exe, c-shared or c-archive. The c-shared and c-archive modes build a library (.dll/.so/.dylib or .a) and its C header")
+	flag.Var(&cgoCFLAGS, "cgo-cflags", "The flags passed to the C compiler as CGO_CFLAGS, in the form [target:]flags. ${APP_DIR} is the container path of the package root directory. Can be repeated")
+	flag.Var(&cgoLDFLAGS, "cgo-ldflags", "The flags passed to the C linker as CGO_LDFLAGS, in the form [target:]flags. ${APP_DIR} is the container path of the package root directory. Can be repeated")
+	flag.Var(&pkgConfigPath, "pkg-config-path", "A pkg-config folder added to PKG_CONFIG_PATH, in the form [target:]path. ${APP_DIR} is the container path of the package root directory. Can be repeated")
+	flag.BoolVar(&windowsConsole, "windows-console", false, "Build the windows targets as console applications dropping the default -H windowsgui ldflags, i.e. for CLI or debug builds. Default to false")
+	flag.BoolVar(&harden, "harden", false, "Build the linux targets as position independent executables (-buildmode=pie) with full RELRO and immediate binding, as required by the Debian and Fedora packaging guidelines. Default to false")
+	flag.StringVar(&goarm, "goarm", "", "The ARM architecture version of the arm targets: 5, 6 or 7, i.e. 6 for the Raspberry Pi 1 and Zero. Default to the go default")
+	flag.StringVar(&goamd64, "goamd64", "", "The microarchitecture level of the amd64 targets: v1, v2, v3 or v4. Requires Go 1.18 or later, see --go. Default to the go default")
+	flag.BoolVar(&sbomEnabled, "sbom", false, "Generate a CycloneDX SBOM next to each built target. Default to false")
+	flag.BoolVar(&targetSubdir, "target-subdir", false, "Write the artifacts into the build/<goos>-<goarch> subdirectory of each target. Default to false")
+	flag.StringVar(&bundleDir, "bundle-dir", "", "The assets directory to bundle via fyne bundle before compiling, relative to the package root directory. Default to none")
+	flag.StringVar(&bundlePkg, "bundle-package", "main", "The package name of the bundled go file")
+	flag.StringVar(&bundleOutput, "bundle-output", "bundled.go", "The bundled go file, relative to the package root directory")
+	flag.StringVar(&icon, "icon", "", "A square PNG image, of at least 512x512 pixels, used to generate the icns, ico and hicolor icons into the build/icons folder. Default to none")
+	flag.BoolVar(&keepGoing, "keep-going", false, "Continue building the remaining targets when a target fails. A summary is printed and the exit code is non-zero if any target failed. Default to false")
+	flag.StringVar(&appVersion, "app-version", "", "The version of the build recorded into the build manifest, i.e. 1.1.0")
+	flag.StringVar(&deltaFrom, "delta-from", "", "The build folder of a previous release. The binary diffs from its executables are written into build/delta along with the update manifest. Requires --app-version")
+	flag.Var(&packagerNames, "packager", "A packager to run on the built artifacts. Packagers not compiled in are run as the fyne-cross-<name> executable found in PATH. Can be repeated")
+	flag.Var(&publisherNames, "publisher", "A publisher to run on the artifacts once all the targets are built. Publishers not compiled in are run as the fyne-cross-<name> executable found in PATH. Can be repeated")
+	flag.BoolVar(&noColor, "no-color", false, "Disable the colors of the target prefixes of the build output lines, i.e. for CI logs. Colors are enabled only on terminals and when NO_COLOR is not set")
+	flag.BoolVar(&githubOutput, "github-output", false, "Write the artifact paths, names and checksums as step outputs to $GITHUB_OUTPUT and emit the GitHub Actions ::group:: and ::error:: annotations. Default to false")
+	flag.BoolVar(&force, "force", false, "Force rebuilding of targets and packages that are already up-to-date. Default to false")
+}
+
+// addTargetsFlag adds the flag to select the targets.
+// action describes what the command does for the targets, i.e. build
+func addTargetsFlag(action string) {
+	defaultTarget := build.DefaultTarget()
+	flag.StringVar(&targetList, "targets", defaultTarget, fmt.Sprintf("The list of targets to %s separated by comma. Default to current GOOS/GOARCH %s", action, defaultTarget))
+}
+
+// addCommonFlags adds the flags shared by the commands running into the docker container
+func addCommonFlags() {
 	flag.StringVar(&pkgRootDir, "dir", "", "The package root directory. Default current dir")
 	flag.StringVar(&cacheDir, "cache-dir", "", "The directory used to cache package dependencies. Default to system cache root directory (i.e. $HOME/.cache)")
 	flag.BoolVar(&verbose, "v", false, "Enable verbosity flag for go commands. Default to false")
-	flag.StringVar(&ldflags, "ldflags", "", "flags to pass to the external linker")
+	flag.StringVar(&selinuxLabel, "selinux-label", "auto", "The SELinux label option for the volume mounts: z (shared), Z (private) or none. Default to z when SELinux is enforcing on the host")
+	flag.StringVar(&httpProxy, "http-proxy", hostProxyEnv("HTTP_PROXY"), "The proxy for HTTP requests. Default to the host HTTP_PROXY env variable")
+	flag.StringVar(&httpsProxy, "https-proxy", hostProxyEnv("HTTPS_PROXY"), "The proxy for HTTPS requests. Default to the host HTTPS_PROXY env variable")
+	flag.StringVar(&noProxy, "no-proxy", hostProxyEnv("NO_PROXY"), "The comma separated list of hosts excluded from proxying. Default to the host NO_PROXY env variable")
+	flag.Var(&caCerts, "ca-cert", "A custom CA certificate file to add to the container trust store. Can be repeated")
+	flag.Var(&volumes, "volume", "An additional volume mounted into the container in the form host:container[:ro], i.e. for assets or local replace modules outside the package root directory. Can be repeated")
+	flag.DurationVar(&timeout, "timeout", 0, "The timeout of each build step, i.e. the image pull, the dependencies download or the build of a target, i.e. 30m. Default to none")
+	flag.IntVar(&retries, "retries", 0, "The number of times the image pull and the dependencies download are retried on failure, with exponential backoff. Default to 0")
+	flag.StringVar(&cpus, "cpus", "", "The number of CPUs available to the containers, i.e. 1.5. Default to no limit")
+	flag.StringVar(&memory, "memory", "", "The memory limit of the containers, i.e. 4g. Default to no limit")
+	flag.StringVar(&network, "network", "", "The network of the build container, i.e. none for hermetic builds. The dependencies are downloaded before with network access. Default to the docker default network")
+	flag.StringVar(&goVersion, "go", "", "The Go version to build with, i.e. 1.16.3. The toolchain is downloaded once into the cache directory. Default to the Go version of the image")
+	flag.StringVar(&image, "image", build.DefaultImage, "The docker image used to build, i.e. built locally via fyne-cross image build")
+	flag.Var(&aptPackages, "apt-package", "An extra system package to install into the image, i.e. libvlc-dev. The image with the packages is built once and reused. Can be repeated")
+	flag.BoolVar(&ccacheEnabled, "ccache", false, "Compile the C code via ccache. The cache is stored into the cache directory. Default to false")
+	addEnvFlags()
+	addUserFlags()
+}
+
+// addUserFlags adds the flags to set the user running the commands into the docker container
+func addUserFlags() {
+	flag.StringVar(&containerUser, "user", "", "The user running the commands into the container, as a name or a numeric id. Default to the current user, or the container root user on rootless engines")
+	flag.StringVar(&containerGroup, "group", "", "The group running the commands into the container, as a name or a numeric id. Default to the primary group of the user")
+}
+
+// addEnvFlags adds the flags to export env variables into the docker container
+func addEnvFlags() {
+	flag.Var(&envVars, "env", "A KEY=VALUE env variable to export into the container. Takes precedence over the env file. Can be repeated")
+	flag.StringVar(&envFile, "env-file", "", "The env file, relative to the package root directory, whose KEY=VALUE variables are exported into the container. Default to .env, if exists")
 }
 
 func (b *builder) printHelp(indent string) {
-	fmt.Println("Usage: fyne-cross [parameters] package")
+	fmt.Println("Usage: fyne-cross [command] [parameters] [packages]")
 	fmt.Println()
 	fmt.Println("Cross compile a Fyne application")
 	fmt.Println()
 
-	fmt.Println("Package is the relative path to main.go file or main package. Default to '.'")
+	fmt.Println("Packages are the relative paths to main.go file or main package. A pattern ending with /... builds all the main packages below the directory. Default to '.'")
 	fmt.Println()
 
 	fmt.Println("Optional parameters:")
@@ -75,290 +198,116 @@ func (b *builder) printHelp(indent string) {
 	fmt.Println()
 
 	fmt.Println("Supported targets:")
-	for target := range targetWithBuildOpts {
+	for _, target := range build.SupportedTargets() {
 		fmt.Println(indent, "- ", target)
 	}
 	fmt.Println()
 
 	fmt.Println("Default ldflags per target:")
-	for target, ldflags := range targetLdflags {
-		fmt.Println(indent, "- ", target, ldflags)
+	for _, target := range build.SupportedTargets() {
+		if ldflags := build.DefaultLdflags(target); ldflags != "" {
+			fmt.Println(indent, "- ", target, ldflags)
+		}
 	}
 	fmt.Println()
 
-	fmt.Println("Example: fyne-cross --targets=linux/amd64,windows/amd64 --output=test ./cmd/test")
-}
-
-func (b *builder) run(args []string) {
-	var err error
-
-	targets, err := parseTargets(targetList)
-	if err != nil {
-		fmt.Printf("Unable to parse targets option %s", err)
-		os.Exit(1)
+	fmt.Println("Commands:")
+	names := []string{}
+	for name := range commands {
+		names = append(names, name)
 	}
-
-	if pkgRootDir == "" {
-		pkgRootDir, err = os.Getwd()
-		if err != nil {
-			fmt.Printf("Cannot get the path for current directory %s", err)
-			os.Exit(1)
-		}
-	}
-
-	if cacheDir == "" {
-		cacheDir, err = os.UserCacheDir()
-		if err != nil {
-			fmt.Printf("Cannot get the path for cache directory %s", err)
-			os.Exit(1)
-		}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Println(indent, "- ", name)
 	}
+	fmt.Println("Use 'fyne-cross <command> help' for more information about a command")
+	fmt.Println()
 
-	pkg := args[0]
-	if pkg == "" {
-		pkg, err = os.Getwd()
-		if err != nil {
-			fmt.Printf("Cannot get the path for current directory %s", err)
-			os.Exit(1)
-		}
-	}
+	fmt.Println("Example: fyne-cross --targets=linux/amd64,windows/amd64 --output=test ./cmd/test")
+	fmt.Println("Example: fyne-cross --targets=linux/amd64,windows/amd64 ./cmd/...")
+}
 
-	db := dockerBuilder{
-		pkg:      pkg,
-		workDir:  pkgRootDir,
-		cacheDir: cacheDir,
-		targets:  targets,
-		output:   output,
-		verbose:  verbose,
-		ldflags:  ldflags,
+func (b *builder) run(args []string) {
+	pkgs := []string{"."}
+	if len(args) > 0 {
+		pkgs = args
 	}
 
-	err = db.checkRequirements()
+	db, err := newBuilder(pkgs)
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
 
-	fmt.Println("Downloading dependencies")
-	err = db.goGet()
+	ctx, cancel := signalContext()
+	defer cancel()
+	_, err = db.Build(ctx)
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
-	}
-
-	fmt.Printf("Build output folder: %s/build\n", db.workDir)
-	for _, target := range targets {
-		fmt.Printf("Building for %s\n", target)
-		err = db.goBuild(target)
-		if err != nil {
+		// already reported as error annotation in the GitHub output mode
+		if !githubOutput {
 			fmt.Println(err)
-			os.Exit(1)
-		}
-		t, _ := db.targetOutput(target)
-		fmt.Printf("Built as %s\n", t)
-	}
-}
-
-// dockerBuilder represents the docker builder
-type dockerBuilder struct {
-	targets  []string
-	output   string
-	pkg      string
-	workDir  string
-	cacheDir string
-	verbose  bool
-	ldflags  string
-}
-
-// checkRequirements checks if all the build requirements are satisfied
-func (d *dockerBuilder) checkRequirements() error {
-	err := exec.Command("docker", "version").Run()
-	if err != nil {
-		return fmt.Errorf("Missed requirement: docker binary not found in PATH")
-	}
-	return nil
-}
-
-// goGet downloads the application dependencies via go get
-func (d *dockerBuilder) goGet() error {
-	args := append(d.defaultArgs(), d.goGetArgs()...)
-	if d.verbose {
-		fmt.Printf("docker %s\n", strings.Join(args, " "))
-	}
-	cmd := exec.Command("docker", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
-}
-
-// goBuild runs the go build for target
-func (d *dockerBuilder) goBuild(target string) error {
-	buildArgs, err := d.goBuildArgs(target)
-	if err != nil {
-		return err
-	}
-
-	args := append(d.defaultArgs(), buildArgs...)
-	if d.verbose {
-		fmt.Printf("docker %s\n", strings.Join(args, " "))
-	}
-	cmd := exec.Command("docker", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
-}
-
-// targetOutput returns the output file for the specified target.
-// Default prefix is the package name. To override use the output option.
-// Example: fyne-linux-amd64
-func (d *dockerBuilder) targetOutput(target string) (string, error) {
-	output := d.output
-	if output == "" {
-		if d.pkg == "." {
-			files, err := filepath.Glob("./*.go")
-			if err != nil {
-				return "", err
-			}
-			if len(files) == 0 {
-				return "", fmt.Errorf("Cannot found go files in current dir")
-			}
-
-			output = strings.TrimSuffix(files[0], ".go")
-		} else {
-			parts := strings.Split(d.pkg, "/")
-			output = parts[len(parts)-1]
 		}
+		os.Exit(1)
 	}
-
-	normalizedTarget := strings.Replace(target, "/", "-", -1)
-
-	ext := ""
-	if strings.HasPrefix(target, "windows") {
-		ext = ".exe"
-	}
-	return fmt.Sprintf("%s-%s%s", output, normalizedTarget, ext), nil
-}
-
-// verbosityFlag returns the string used to set verbosity with go commands
-// according to current setting
-func (d *dockerBuilder) verbosityFlag() string {
-	v := ""
-	if d.verbose {
-		v = "-v"
-	}
-	return v
-}
-
-// defaultArgs returns the default arguments used to run a go command into the
-// docker container
-func (d *dockerBuilder) defaultArgs() []string {
-	args := []string{
-		"run",
-		"--rm",
-		"-t",
-	}
-
-	// set workdir
-	args = append(args, "-w", fmt.Sprintf("/app"))
-
-	// mount root dir package under image GOPATH/src
-	args = append(args, "-v", fmt.Sprintf("%s:/app", d.workDir))
-
-	// mount the cache user dir. Used to cache package dependencies (GOROOT/pkg and GOROOT/src)
-	args = append(args, "-v", fmt.Sprintf("%s/fyne-cross:/go", d.cacheDir))
-
-	// attempt to set fyne user id as current user id to handle mount permissions
-	u, err := user.Current()
-	if err == nil {
-		args = append(args, "-e", fmt.Sprintf("fyne_uid=%s", u.Uid))
-	}
-
-	return args
 }
 
-// goGetArgs returns the arguments for the "go get" command
-func (d *dockerBuilder) goGetArgs() []string {
-	buildCmd := fmt.Sprintf("go get %s -d ./...", d.verbosityFlag())
-	return []string{dockerImage, buildCmd}
-}
-
-// goGetArgs returns the arguments for the "go build" command for target
-func (d *dockerBuilder) goBuildArgs(target string) ([]string, error) {
-	// Start adding env variables
-	args := []string{
-		// enable CGO
-		"-e", "CGO_ENABLED=1",
-	}
-
-	// add default compile target options env variables
-	if buildOpts, ok := targetWithBuildOpts[target]; ok {
-		for _, o := range buildOpts {
-			args = append(args, "-e", o)
-		}
-	}
-
-	// add docker image
-	args = append(args, dockerImage)
-
-	// add go build command
-	args = append(args, "go", "build")
-
-	// Start adding ldflags
-	ldflags := []string{}
-	// add defaults
-	if ldflagsDefault, ok := targetLdflags[target]; ok {
-		ldflags = append(ldflags, ldflagsDefault)
-	}
-	// add custom ldflags
-	if d.ldflags != "" {
-		ldflags = append(ldflags, d.ldflags)
-	}
-
-	// add ldflags to command, if any
-	if len(ldflags) > 0 {
-		args = append(args, "-ldflags", fmt.Sprintf("'%s'", strings.Join(ldflags, " ")))
-	}
-
-	// add target output
-	targetOutput, err := d.targetOutput(target)
+// newBuilder returns the builder for pkgs configured from the command line flags
+func newBuilder(pkgs []string) (*build.Builder, error) {
+	targets, err := build.ParseTargets(targetList)
 	if err != nil {
-		return []string{}, err
-	}
-	args = append(args, "-o", fmt.Sprintf("build/%s", targetOutput))
-
-	// add force compile option
-	args = append(args, "-a")
-
-	// add force compile option
-	if d.verbose {
-		args = append(args, "-v")
-	}
-
-	// add package
-	args = append(args, d.pkg)
-	return args, nil
-}
-
-// parseTargets parse comma separated target list and validate against the supported targets
-func parseTargets(targetList string) ([]string, error) {
-	targets := []string{}
-
-	for _, target := range strings.Split(targetList, ",") {
-		target = strings.TrimSpace(target)
-
-		var isValid bool
-		for oktarget := range targetWithBuildOpts {
-			if target == oktarget {
-				isValid = true
-				targets = append(targets, target)
-				break
-			}
-		}
-
-		if isValid == false {
-			return targets, fmt.Errorf("Unsupported target %q", target)
-		}
+		return nil, fmt.Errorf("Unable to parse targets option %s", err)
 	}
 
-	return targets, nil
+	return build.NewBuilder(build.Options{
+		Targets:        targets,
+		Packages:       pkgs,
+		Output:         output,
+		Dir:            pkgRootDir,
+		CacheDir:       cacheDir,
+		Verbose:        verbose,
+		Ldflags:        ldflags,
+		BuildMode:      buildMode,
+		Harden:         harden,
+		WindowsConsole: windowsConsole,
+		Cgo:            cgoSettings(cgoCFLAGS, cgoLDFLAGS, pkgConfigPath),
+		GOARM:          goarm,
+		GOAMD64:        goamd64,
+		Force:          force,
+		KeepGoing:      keepGoing,
+		CCache:         ccacheEnabled,
+		SBOM:           sbomEnabled,
+		TargetSubdir:   targetSubdir,
+		SELinuxLabel:   selinuxLabel,
+		CACerts:        caCerts,
+		Volumes:        volumes,
+		User:           containerUser,
+		Group:          containerGroup,
+		Env:            envVars,
+		EnvFile:        envFile,
+		Icon:           icon,
+		Proxy: build.Proxy{
+			HTTP:    httpProxy,
+			HTTPS:   httpsProxy,
+			NoProxy: noProxy,
+		},
+		Bundle: build.Bundle{
+			Dir:     bundleDir,
+			Package: bundlePkg,
+			Output:  bundleOutput,
+		},
+		Image:        image,
+		GoVersion:    goVersion,
+		Retries:      retries,
+		CPUs:         cpus,
+		Memory:       memory,
+		Network:      network,
+		AptPackages:  aptPackages,
+		Version:      appVersion,
+		DeltaFrom:    deltaFrom,
+		Packagers:    packagerNames,
+		Publishers:   publisherNames,
+		GitHubOutput: githubOutput,
+		NoColor:      noColor,
+		Timeout:      timeout,
+	})
 }
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/lucor/fyne-cross/pkg/build"
+)
+
+var (
+	// pruneOlderThan represents the age of the cache entries to prune
+	pruneOlderThan time.Duration
+	// pruneMaxSize represents the size limit of the cache
+	pruneMaxSize string
+)
+
+// sizeUnits represents the units accepted by parseSize
+var sizeUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	{"TB", 1 << 40},
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"B", 1},
+}
+
+// cacher is the command managing the fyne-cross cache directory
+type cacher struct{}
+
+func (c *cacher) addFlags() {
+	flag.StringVar(&cacheDir, "cache-dir", "", "The directory used to cache package dependencies. Default to system cache root directory (i.e. $HOME/.cache)")
+	flag.DurationVar(&pruneOlderThan, "older-than", 0, "Prune the entries not used since the specified duration, i.e. 720h. Default to none")
+	flag.StringVar(&pruneMaxSize, "max-size", "", "Prune the least recently used entries until the cache is under the specified size, i.e. 10GB. Default to none")
+}
+
+func (c *cacher) printHelp(indent string) {
+	fmt.Println("Usage: fyne-cross cache [parameters] size|prune [components]")
+	fmt.Println("       fyne-cross cache [parameters] export <tarball> [components]")
+	fmt.Println("       fyne-cross cache [parameters] import <tarball>")
+	fmt.Println()
+	fmt.Println("Show the size of the cache directory components or prune them.")
+	fmt.Println("The prune removes all the entries when neither --older-than nor --max-size is set and waits for the running builds to complete")
+	fmt.Println("The export writes the components to a gzipped tarball, the import restores them keeping the existing entries.")
+	fmt.Println("Use - as tarball to write to the standard output or read from the standard input")
+	fmt.Println()
+
+	fmt.Println("Components:")
+	for _, name := range build.CacheComponents() {
+		fmt.Println(indent, "- ", name)
+	}
+	fmt.Println()
+
+	fmt.Println("Optional parameters:")
+	flag.PrintDefaults()
+	fmt.Println()
+
+	fmt.Println("Example: fyne-cross cache --older-than=720h prune gocache ccache")
+	fmt.Println("Example: fyne-cross cache export fyne-cross-cache.tgz modules gocache")
+}
+
+func (c *cacher) run(args []string) {
+	if len(args) == 0 {
+		printUsage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch args[0] {
+	case "size":
+		err = printCacheSize(args[1:])
+	case "prune":
+		err = pruneCache(args[1:])
+	case "export", "import":
+		if len(args) < 2 || (args[0] == "import" && len(args) > 2) {
+			printUsage()
+			os.Exit(2)
+		}
+		if args[0] == "export" {
+			err = exportCache(args[1], args[2:])
+		} else {
+			err = importCache(args[1])
+		}
+	default:
+		printUsage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// printCacheSize prints the size of the cache components, all if none is specified
+func printCacheSize(components []string) error {
+	usage, err := build.CacheSize(cacheDir)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "COMPONENT\tSIZE\tDIR")
+	var total int64
+	for _, u := range usage {
+		if len(components) > 0 && !contains(components, u.Component) {
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", u.Component, formatSize(u.Size), u.Dir)
+		total += u.Size
+	}
+	fmt.Fprintf(tw, "total\t%s\t\n", formatSize(total))
+	return tw.Flush()
+}
+
+// pruneCache prunes the cache components according to the flags, all if none is specified
+func pruneCache(components []string) error {
+	opts := build.PruneOptions{
+		Components: components,
+		OlderThan:  pruneOlderThan,
+	}
+	if pruneMaxSize != "" {
+		size, err := parseSize(pruneMaxSize)
+		if err != nil {
+			return err
+		}
+		opts.MaxSize = size
+	}
+
+	freed, err := build.PruneCache(cacheDir, opts)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Pruned %s\n", formatSize(freed))
+	return nil
+}
+
+// exportCache writes the cache components, all if none is specified, to the tarball file.
+// The file is removed if the export fails, so that a partial archive is not persisted
+func exportCache(file string, components []string) error {
+	if file == "-" {
+		return build.ExportCache(cacheDir, os.Stdout, components)
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("Cannot create the cache archive %s", err)
+	}
+	err = build.ExportCache(cacheDir, f, components)
+	if err == nil {
+		err = f.Close()
+	} else {
+		f.Close()
+	}
+	if err != nil {
+		os.Remove(file)
+		return err
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Exported %s to %s\n", formatSize(info.Size()), file)
+	return nil
+}
+
+// importCache restores the cache components from the tarball file
+func importCache(file string) error {
+	if file == "-" {
+		return build.ImportCache(cacheDir, os.Stdin)
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("Cannot open the cache archive %s", err)
+	}
+	defer f.Close()
+	err = build.ImportCache(cacheDir, f)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Imported %s\n", file)
+	return nil
+}
+
+// parseSize parses a size with an optional unit, i.e. 512MB or 10GB
+func parseSize(s string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	multiplier := int64(1)
+	for _, u := range sizeUnits {
+		if strings.HasSuffix(value, u.suffix) {
+			value = strings.TrimSpace(strings.TrimSuffix(value, u.suffix))
+			multiplier = u.bytes
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("Invalid size %q, expected a positive size i.e. 10GB", s)
+	}
+	return int64(n * float64(multiplier)), nil
+}
+
+// formatSize formats the bytes using the largest unit with at least one unit
+func formatSize(bytes int64) string {
+	for _, u := range sizeUnits[:len(sizeUnits)-1] {
+		if bytes >= u.bytes {
+			return fmt.Sprintf("%.1f%s", float64(bytes)/float64(u.bytes), u.suffix)
+		}
+	}
+	return fmt.Sprintf("%dB", bytes)
+}
+
+// contains returns true if values contains value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package main
+
+import "testing"
+
+func Test_parseSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    string
+		want    int64
+		wantErr bool
+	}{
+		{name: "bytes", size: "512", want: 512},
+		{name: "megabytes", size: "512MB", want: 512 << 20},
+		{name: "gigabytes lower case", size: "10gb", want: 10 << 30},
+		{name: "fraction", size: "1.5GB", want: 3 << 29},
+		{name: "invalid", size: "ten GB", wantErr: true},
+		{name: "negative", size: "-1GB", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSize(tt.size)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseSize() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("parseSize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_formatSize(t *testing.T) {
+	tests := []struct {
+		bytes int64
+		want  string
+	}{
+		{bytes: 0, want: "0B"},
+		{bytes: 512, want: "512B"},
+		{bytes: 1536, want: "1.5KB"},
+		{bytes: 10 << 30, want: "10.0GB"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := formatSize(tt.bytes); got != tt.want {
+				t.Errorf("formatSize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
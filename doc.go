@@ -10,6 +10,7 @@ Supported targets are:
   -  darwin/386
   -  linux/amd64
   -  linux/386
+  -  linux/arm
   -  windows/amd64
 
 */
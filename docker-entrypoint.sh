@@ -1,10 +1,20 @@
 #!/bin/sh
 set -e
 
-if [ -n "$fyne_uid" ]; then
-    adduser -q --disabled-password --gecos "" --uid $fyne_uid fyne 
-    chown $fyne_uid /go
-    eval exec gosu $fyne_uid $@
+# add the custom CA certificates, if any, to the trust store
+if [ -d /usr/local/share/ca-certificates/fyne-cross ]; then
+    update-ca-certificates > /dev/null
 fi
 
-eval exec $@
+# run as the requested uid:gid, if any. The user could have no entry into the passwd file,
+# so the home folder is created and set explicitly
+if [ -n "$fyne_user" ]; then
+    export HOME=/tmp/fyne-home
+    mkdir -p "$HOME"
+    chown "$fyne_user" "$HOME"
+    touch /tmp/fyne-cross.ready
+    exec gosu "$fyne_user" env HOME="$HOME" "$@"
+fi
+
+touch /tmp/fyne-cross.ready
+exec "$@"
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/lucor/fyne-cross/pkg/build"
+)
+
+// stringSliceFlag is a flag.Value that collects the values of a repeatable flag
+type stringSliceFlag []string
+
+func (s *stringSliceFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringSliceFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// hostProxyEnv returns the value of the proxy env variable set on the host.
+// The upper case variant takes precedence
+func hostProxyEnv(name string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return os.Getenv(strings.ToLower(name))
+}
+
+// splitTargetValue splits the value of a per target flag in the form [target:]value.
+// The target is build.AllTargets when the value has no supported target prefix
+func splitTargetValue(v string) (string, string) {
+	parts := strings.SplitN(v, ":", 2)
+	if len(parts) == 2 {
+		for _, target := range build.SupportedTargets() {
+			if parts[0] == target {
+				return target, parts[1]
+			}
+		}
+	}
+	return build.AllTargets, v
+}
+
+// cgoSettings returns the cgo settings by target from the values of the per target
+// cgo flags. Repeated values for the same target are joined
+func cgoSettings(cflags []string, ldflags []string, pkgConfigPath []string) map[string]build.Cgo {
+	settings := map[string]build.Cgo{}
+	join := func(current string, sep string, value string) string {
+		if current == "" {
+			return value
+		}
+		return current + sep + value
+	}
+	for _, v := range cflags {
+		target, value := splitTargetValue(v)
+		s := settings[target]
+		s.CFLAGS = join(s.CFLAGS, " ", value)
+		settings[target] = s
+	}
+	for _, v := range ldflags {
+		target, value := splitTargetValue(v)
+		s := settings[target]
+		s.LDFLAGS = join(s.LDFLAGS, " ", value)
+		settings[target] = s
+	}
+	for _, v := range pkgConfigPath {
+		target, value := splitTargetValue(v)
+		s := settings[target]
+		s.PkgConfigPath = join(s.PkgConfigPath, ":", value)
+		settings[target] = s
+	}
+	return settings
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lucor/fyne-cross/pkg/build"
+)
+
+func Test_cgoSettings(t *testing.T) {
+	cflags := []string{"-I${APP_DIR}/include", "windows/amd64:-DWIN64", "windows/amd64:-DUNICODE"}
+	ldflags := []string{"linux/amd64:-L${APP_DIR}/lib -lfoo"}
+	pkgConfigPath := []string{"/opt/a:/opt/b", "linux/386:/usr/lib/i386-linux-gnu/pkgconfig"}
+
+	want := map[string]build.Cgo{
+		build.AllTargets: {CFLAGS: "-I${APP_DIR}/include", PkgConfigPath: "/opt/a:/opt/b"},
+		"windows/amd64":  {CFLAGS: "-DWIN64 -DUNICODE"},
+		"linux/amd64":    {LDFLAGS: "-L${APP_DIR}/lib -lfoo"},
+		"linux/386":      {PkgConfigPath: "/usr/lib/i386-linux-gnu/pkgconfig"},
+	}
+	if got := cgoSettings(cflags, ldflags, pkgConfigPath); !reflect.DeepEqual(got, want) {
+		t.Errorf("cgoSettings() = %v, want %v", got, want)
+	}
+}
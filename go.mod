@@ -1,3 +1,3 @@
 module github.com/lucor/fyne-cross
 
-go 1.12
+go 1.16
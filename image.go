@@ -0,0 +1,128 @@
+package main
+
+import (
+	"embed"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/lucor/fyne-cross/pkg/build"
+)
+
+// imageFiles are the files of the docker build context of the fyne-cross image
+//
+//go:embed Dockerfile docker-entrypoint.sh
+var imageFiles embed.FS
+
+var (
+	// imageTag represents the tag of the image to build
+	imageTag string
+	// imageBase represents the base image
+	imageBase string
+	// imagePull represents the setting to pull the newer version of the base image
+	imagePull bool
+	// imageNoCache represents the setting to build without the docker build cache
+	imageNoCache bool
+)
+
+// imager is the command managing the fyne-cross docker image
+type imager struct{}
+
+func (i *imager) addFlags() {
+	flag.StringVar(&imageTag, "tag", build.DefaultImage, "The tag of the image to build, use it with the --image flag of the other commands")
+	flag.StringVar(&imageBase, "base", "", "The base image, i.e. a golang-cross image mirrored into a private registry. Default to the image of the Dockerfile")
+	flag.BoolVar(&imagePull, "pull", false, "Pull the newer version of the base image. Default to false")
+	flag.BoolVar(&imageNoCache, "no-cache", false, "Do not use the docker build cache. Default to false")
+	flag.BoolVar(&verbose, "v", false, "Enable verbosity. Default to false")
+}
+
+func (i *imager) printHelp(indent string) {
+	fmt.Println("Usage: fyne-cross image [parameters] build")
+	fmt.Println()
+	fmt.Println("Build the fyne-cross docker image locally from the embedded Dockerfile")
+	fmt.Println()
+
+	fmt.Println("Optional parameters:")
+	flag.PrintDefaults()
+	fmt.Println()
+
+	fmt.Println("Example: fyne-cross image --tag=registry.example.com/fyne-cross --base=registry.example.com/golang-cross:1.12.6 build")
+}
+
+func (i *imager) run(args []string) {
+	if len(args) != 1 || args[0] != "build" {
+		printUsage()
+		os.Exit(2)
+	}
+
+	err := buildImage()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Printf("Image built as %s\n", imageTag)
+}
+
+// buildImage builds the image from the embedded docker build context
+func buildImage() error {
+	dir, err := ioutil.TempDir("", "fyne-cross-image")
+	if err != nil {
+		return fmt.Errorf("Cannot create the docker build context %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	err = writeImageFiles(dir)
+	if err != nil {
+		return fmt.Errorf("Cannot write the docker build context %s", err)
+	}
+
+	args := imageBuildArgs(imageTag, imageBase, imagePull, imageNoCache, dir)
+	if verbose {
+		fmt.Printf("docker %s\n", strings.Join(args, " "))
+	}
+	cmd := exec.Command("docker", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err = cmd.Run()
+	if err != nil {
+		return fmt.Errorf("Cannot build the image %s", err)
+	}
+	return nil
+}
+
+// writeImageFiles writes the embedded docker build context into dir
+func writeImageFiles(dir string) error {
+	for name, mode := range map[string]os.FileMode{
+		"Dockerfile":           0644,
+		"docker-entrypoint.sh": 0755,
+	} {
+		b, err := imageFiles.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(filepath.Join(dir, name), b, mode)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// imageBuildArgs returns the arguments for the "docker build" command of the image
+func imageBuildArgs(tag string, base string, pull bool, noCache bool, dir string) []string {
+	args := []string{"build", "-t", tag}
+	if base != "" {
+		args = append(args, "--build-arg", "BASE_IMAGE="+base)
+	}
+	if pull {
+		args = append(args, "--pull")
+	}
+	if noCache {
+		args = append(args, "--no-cache")
+	}
+	return append(args, dir)
+}
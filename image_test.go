@@ -0,0 +1,73 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_imageBuildArgs(t *testing.T) {
+	type args struct {
+		tag     string
+		base    string
+		pull    bool
+		noCache bool
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "default",
+			args: args{tag: "lucor/fyne-cross"},
+			want: []string{"build", "-t", "lucor/fyne-cross", "/tmp/ctx"},
+		},
+		{
+			name: "custom base",
+			args: args{tag: "registry.example.com/fyne-cross", base: "registry.example.com/golang-cross:1.12.6", pull: true, noCache: true},
+			want: []string{
+				"build", "-t", "registry.example.com/fyne-cross",
+				"--build-arg", "BASE_IMAGE=registry.example.com/golang-cross:1.12.6",
+				"--pull", "--no-cache",
+				"/tmp/ctx",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := imageBuildArgs(tt.args.tag, tt.args.base, tt.args.pull, tt.args.noCache, "/tmp/ctx"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("imageBuildArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_writeImageFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fyne-cross")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	err = writeImageFiles(dir)
+	if err != nil {
+		t.Fatalf("writeImageFiles() error = %v", err)
+	}
+
+	for _, name := range []string{"Dockerfile", "docker-entrypoint.sh"} {
+		want, err := ioutil.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("writeImageFiles() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("writeImageFiles() %s differs from the embedded file", name)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lucor/fyne-cross/pkg/build"
+)
+
+// ciWorkflowFile is the GitHub Actions workflow file scaffolded by "init ci"
+// relative to the package root directory
+const ciWorkflowFile = ".github/workflows/fyne-cross.yml"
+
+// initer is the command scaffolding the fyne-cross configuration files
+type initer struct{}
+
+func (i *initer) addFlags() {
+	flag.StringVar(&targetList, "targets", strings.Join(build.SupportedTargets(), ","), "The list of targets to build separated by comma. Default to all the supported targets")
+	flag.StringVar(&pkgRootDir, "dir", "", "The package root directory. Default current dir")
+	flag.BoolVar(&force, "force", false, "Overwrite the existing files. Default to false")
+}
+
+func (i *initer) printHelp(indent string) {
+	fmt.Println("Usage: fyne-cross init [parameters] ci [package]")
+	fmt.Println()
+	fmt.Println("Scaffold the fyne-cross configuration files")
+	fmt.Println()
+
+	fmt.Println("Configurations:")
+	fmt.Println(indent, "- ", "ci: a GitHub Actions workflow building each target and uploading the artifacts")
+	fmt.Println()
+
+	fmt.Println("Package is the package to build. Default to '.'")
+	fmt.Println()
+
+	fmt.Println("Optional parameters:")
+	flag.PrintDefaults()
+	fmt.Println()
+
+	fmt.Println("Example: fyne-cross init --targets=linux/amd64,windows/amd64 ci ./cmd/test")
+}
+
+func (i *initer) run(args []string) {
+	if len(args) == 0 || len(args) > 2 || args[0] != "ci" {
+		printUsage()
+		os.Exit(2)
+	}
+
+	pkg := "."
+	if len(args) > 1 {
+		pkg = args[1]
+	}
+
+	targets, err := build.ParseTargets(targetList)
+	if err != nil {
+		fmt.Printf("Unable to parse targets option %s\n", err)
+		os.Exit(1)
+	}
+
+	if pkgRootDir == "" {
+		pkgRootDir, err = os.Getwd()
+		if err != nil {
+			fmt.Printf("Cannot get the path for current directory %s\n", err)
+			os.Exit(1)
+		}
+	}
+
+	file := filepath.Join(pkgRootDir, filepath.FromSlash(ciWorkflowFile))
+	_, err = os.Stat(file)
+	if err == nil && !force {
+		fmt.Printf("The %s file already exists, use --force to overwrite\n", file)
+		os.Exit(1)
+	}
+
+	err = os.MkdirAll(filepath.Dir(file), 0755)
+	if err == nil {
+		err = ioutil.WriteFile(file, []byte(ciWorkflow(targets, pkg)), 0644)
+	}
+	if err != nil {
+		fmt.Printf("Cannot write the workflow file %s\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("GitHub Actions workflow written as %s\n", file)
+}
+
+// ciWorkflow returns the GitHub Actions workflow building pkg for each target.
+// The built artifacts are uploaded as workflow artifacts via the fyne-cross step outputs
+func ciWorkflow(targets []string, pkg string) string {
+	var b strings.Builder
+	b.WriteString(`name: fyne-cross
+
+on:
+  push:
+    branches:
+      - main
+    tags:
+      - 'v*'
+  pull_request:
+
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    strategy:
+      fail-fast: false
+      matrix:
+        target:
+`)
+	for _, target := range targets {
+		fmt.Fprintf(&b, "          - %s\n", target)
+	}
+	fmt.Fprintf(&b, `    steps:
+      - uses: actions/checkout@v4
+      - uses: actions/setup-go@v5
+        with:
+          go-version: stable
+      - name: Install fyne-cross
+        run: go install github.com/lucor/fyne-cross@latest
+      - name: Build
+        id: build
+        run: fyne-cross --targets=${{ matrix.target }} --github-output %s
+      - name: Artifact name
+        id: artifact
+        run: echo "name=$(echo ${{ matrix.target }} | tr / -)" >> "$GITHUB_OUTPUT"
+      - uses: actions/upload-artifact@v4
+        with:
+          name: ${{ steps.artifact.outputs.name }}
+          path: ${{ steps.build.outputs.paths }}
+`, pkg)
+	return b.String()
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_ciWorkflow(t *testing.T) {
+	got := ciWorkflow([]string{"linux/amd64", "windows/amd64"}, "./cmd/test")
+
+	for _, want := range []string{
+		"        target:\n          - linux/amd64\n          - windows/amd64\n    steps:\n",
+		"run: fyne-cross --targets=${{ matrix.target }} --github-output ./cmd/test\n",
+		"path: ${{ steps.build.outputs.paths }}\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ciWorkflow() = %v, want to contain %v", got, want)
+		}
+	}
+}
@@ -1,34 +1,65 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
+	"os/signal"
+	"syscall"
 )
 
-var provider *builder
+var (
+	// commands represents the list of the available commands by name
+	commands map[string]command
+	// provider represents the command to run
+	provider command
+)
 
 func printUsage() {
 	provider.printHelp(" ")
 }
 
+// signalContext returns a context canceled on SIGINT or SIGTERM, so that the running containers
+// are removed when the command is interrupted, i.e. by Ctrl-C or a cancelled CI job
+func signalContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
 func main() {
+	commands = map[string]command{
+		"cache":   &cacher{},
+		"image":   &imager{},
+		"init":    &initer{},
+		"publish": &publisher{},
+		"test":    &tester{},
+		"run":     &runner{},
+		"shell":   &sheller{},
+		"targets": &targetLister{},
+		"verify":  &verifier{},
+	}
+
+	// build is the default command
+	provider = &builder{}
+	args := os.Args[1:]
+	if len(args) > 0 {
+		if c, ok := commands[args[0]]; ok {
+			provider = c
+			args = args[1:]
+		}
+	}
+
 	flag.Usage = printUsage
 
 	provider.addFlags()
 
-	flag.Parse()
-
-	args := flag.Args()
-	if len(args) > 1 {
-		printUsage()
-		os.Exit(2)
-	}
+	flag.CommandLine.Parse(args)
 
-	if len(args) == 0 {
-		args = append(args, ".")
+	if _, ok := provider.(*runner); ok {
+		args = keepSeparator(args, flag.Args())
+	} else {
+		args = flag.Args()
 	}
-
-	if args[0] == "help" {
+	if len(args) > 0 && args[0] == "help" {
 		printUsage()
 		os.Exit(2)
 	}
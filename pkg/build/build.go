@@ -0,0 +1,1125 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	gobuild "go/build"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// targetWithBuildOpts represents the list of supported GOOS/GOARCH with the relative
+// options to build
+var targetWithBuildOpts = map[string][]string{
+	"darwin/amd64":  []string{"GOOS=darwin", "GOARCH=amd64", "CC=o32-clang"},
+	"darwin/386":    []string{"GOOS=darwin", "GOARCH=386", "CC=o32-clang"},
+	"linux/amd64":   []string{"GOOS=linux", "GOARCH=amd64", "CC=gcc"},
+	"linux/386":     []string{"GOOS=linux", "GOARCH=386", "CC=gcc"},
+	"linux/arm":     []string{"GOOS=linux", "GOARCH=arm", "CC=arm-linux-gnueabihf-gcc"},
+	"windows/amd64": []string{"GOOS=windows", "GOARCH=amd64", "CC=x86_64-w64-mingw32-gcc"},
+	"windows/386":   []string{"GOOS=windows", "GOARCH=386", "CC=x86_64-w64-mingw32-gcc"},
+}
+
+// hardeningExtldflags represents the external linker flags to build hardened linux targets:
+// full RELRO and immediate binding
+const hardeningExtldflags = "-extldflags '-Wl,-z,relro -Wl,-z,now'"
+
+// targetLdflags represents the list of default ldflags to pass on build
+// for a specified GOOS/GOARCH
+var targetLdflags = map[string]string{
+	"windows/amd64": "-H windowsgui",
+	"windows/386":   "-H windowsgui",
+}
+
+// buildModeExt represents the output file extensions of the supported go build modes
+// for a specified GOOS. The "*" entry is used for the GOOS not listed
+var buildModeExt = map[string]map[string]string{
+	"exe":       {"windows": ".exe", "*": ""},
+	"c-shared":  {"windows": ".dll", "darwin": ".dylib", "*": ".so"},
+	"c-archive": {"*": ".a"},
+}
+
+// microarchitectureLevels represents the supported values of the env variables selecting
+// the microarchitecture level of a GOARCH
+var microarchitectureLevels = map[string][]string{
+	"GOARM":   {"5", "6", "7"},
+	"GOAMD64": {"v1", "v2", "v3", "v4"},
+}
+
+// memoryRegexp matches a docker memory limit, i.e. 512m or 4g
+var memoryRegexp = regexp.MustCompile(`^[1-9][0-9]*[bkmgBKMG]?$`)
+
+// SupportedTargets returns the sorted list of the supported GOOS/GOARCH targets
+func SupportedTargets() []string {
+	targets := []string{}
+	for target := range targetWithBuildOpts {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// DefaultLdflags returns the ldflags passed by default on build for target, if any
+func DefaultLdflags(target string) string {
+	return targetLdflags[target]
+}
+
+// DefaultEnv returns the env variables set by default on build for target, i.e. GOOS, GOARCH and CC
+func DefaultEnv(target string) []string {
+	return append([]string{}, targetWithBuildOpts[target]...)
+}
+
+// DefaultTarget returns the target of the host, i.e. the current GOOS/GOARCH
+func DefaultTarget() string {
+	return strings.Join([]string{gobuild.Default.GOOS, gobuild.Default.GOARCH}, "/")
+}
+
+// ParseTargets parse comma separated target list and validate against the supported targets
+func ParseTargets(targetList string) ([]string, error) {
+	targets := []string{}
+
+	for _, target := range strings.Split(targetList, ",") {
+		target = strings.TrimSpace(target)
+
+		var isValid bool
+		for oktarget := range targetWithBuildOpts {
+			if target == oktarget {
+				isValid = true
+				targets = append(targets, target)
+				break
+			}
+		}
+
+		if isValid == false {
+			return targets, fmt.Errorf("Unsupported target %q", target)
+		}
+	}
+
+	return targets, nil
+}
+
+// Options represents the options used to cross compile a Fyne application
+type Options struct {
+	// Targets is the list of GOOS/GOARCH to build for. Default to the host target
+	Targets []string
+	// Packages are the main packages to build. A relative pattern ending with /... selects
+	// all the main packages below the directory, i.e. ./cmd/... Default to "."
+	Packages []string
+	// Output is the named output file. Default to the package name
+	Output string
+	// Dir is the package root directory. Default to the current directory
+	Dir string
+	// CacheDir is the directory used to cache package dependencies.
+	// Default to the user cache directory (i.e. $HOME/.cache)
+	CacheDir string
+	// Verbose enables the verbosity flag for go commands
+	Verbose bool
+	// Ldflags are the flags to pass to the external linker
+	Ldflags string
+	// BuildMode is the go build mode: exe, c-shared or c-archive. The c-shared and c-archive
+	// modes build a library with its C header, to embed the application into non-Go hosts.
+	// Default to exe
+	BuildMode string
+	// GOARM is the ARM architecture version of the arm targets: 5, 6 or 7, i.e. 6 for the
+	// Raspberry Pi 1 and Zero. Default to the go default
+	GOARM string
+	// GOAMD64 is the microarchitecture level of the amd64 targets: v1, v2, v3 or v4, i.e. v3 for
+	// the x86-64-v3 CPUs. Requires Go 1.18 or later, see GoVersion. Default to the go default
+	GOAMD64 string
+	// Cgo are the cgo compiler and linker settings by target. The settings of the AllTargets key
+	// apply to all the targets and precede the ones of the target
+	Cgo map[string]Cgo
+	// WindowsConsole builds the windows targets as console applications, dropping the default
+	// -H windowsgui ldflags, i.e. for CLI or debug builds showing the console output
+	WindowsConsole bool
+	// Harden builds the linux targets as position independent executables with the RELRO and
+	// immediate binding linker protections, as required by the distro packaging guidelines.
+	// It cannot be used with custom -extldflags into Ldflags
+	Harden bool
+	// Force forces rebuilding of targets and packages that are already up-to-date
+	Force bool
+	// KeepGoing continues building the remaining targets when a target fails
+	KeepGoing bool
+	// CCache compiles the C code via ccache
+	CCache bool
+	// SBOM generates a CycloneDX SBOM next to each built target
+	SBOM bool
+	// TargetSubdir writes the artifacts into the build/<goos>-<goarch> subdirectory of each target
+	TargetSubdir bool
+	// SELinuxLabel is the SELinux label option for the volume mounts: z (shared), Z (private),
+	// none or auto. Default to auto, that is z when SELinux is enforcing on the host
+	SELinuxLabel string
+	// CACerts are the custom CA certificate files to add to the container trust store
+	CACerts []string
+	// Volumes are the additional host folders or files mounted into the container in the form
+	// host:container[:ro], i.e. the assets or the local modules of replace directives located
+	// outside the package root directory. Relative host paths are resolved from the current directory
+	Volumes []string
+	// User is the user running the commands into the container, as a name or a numeric id
+	// looked up on the host. Default to the current user, or the container root user on
+	// rootless engines since it is mapped to the host user
+	User string
+	// Group is the group running the commands into the container, as a name or a numeric id
+	// looked up on the host. Default to the primary group of the user
+	Group string
+	// Proxy represents the proxy settings forwarded into the container
+	Proxy Proxy
+	// Bundle represents the options to bundle the assets via fyne bundle before compiling
+	Bundle Bundle
+	// Icon is a square PNG image, of at least 512x512 pixels, located into the package root directory.
+	// When set the icns (darwin), multi-size ico (windows) and hicolor set (linux) icons
+	// are generated into the build/icons folder
+	Icon string
+	// CPUs is the number of CPUs available to the containers, i.e. 1.5. Default to no limit
+	CPUs string
+	// Memory is the memory limit of the containers, i.e. 4g. Default to no limit
+	Memory string
+	// Network is the network of the build container, i.e. none for hermetic builds.
+	// The dependencies and the toolchain are downloaded into dedicated containers not affected
+	// by this option. Default to the docker default network
+	Network string
+	// Retries is the number of times the network dependent steps, the image pull and the
+	// dependencies and toolchain downloads, are retried on failure with exponential backoff
+	Retries int
+	// GoVersion is the Go version to build with, i.e. 1.16.3. The toolchain is downloaded and cached
+	// into the cache directory. Default to the Go version of the image
+	GoVersion string
+	// Env are the KEY=VALUE env variables exported into the containers.
+	// They take precedence over the variables of the env file
+	Env []string
+	// EnvFile is the env file, relative to the package root directory, whose KEY=VALUE variables
+	// are exported into the containers. Default to .env, loaded only if exists
+	EnvFile string
+	// Image is the docker image used to build, i.e. built locally via "fyne-cross image build".
+	// Default to DefaultImage
+	Image string
+	// AptPackages are the extra system packages to install into the image. The packages are
+	// installed into a derived image built once and reused until the packages or the image change
+	AptPackages []string
+	// Version is the version of the build, recorded into the build manifest, i.e. 1.1.0
+	Version string
+	// DeltaFrom is the folder of a previous release, containing its build manifest and artifacts,
+	// i.e. a copy of its build folder. The binary diffs in the bsdiff format from the previous
+	// executables are written into the build/delta folder along with the update manifest,
+	// for the self-updating applications. Requires the version of both builds
+	DeltaFrom string
+	// Timeout is the timeout of each build step, i.e. the image pull, the dependencies download
+	// or the build of a target. Default to none
+	Timeout time.Duration
+	// Packagers are the names of the packagers to run on the built artifacts, see RegisterPackager
+	Packagers []string
+	// Publishers are the names of the publishers to run on the artifacts, see RegisterPublisher.
+	// Publishers run only when all the targets are built
+	Publishers []string
+	// GitHubOutput writes the artifact paths, names and checksums as step outputs to $GITHUB_OUTPUT
+	// and folds the output of the steps into groups of the GitHub Actions workflow log
+	GitHubOutput bool
+	// NoColor disables the colors of the target prefixes of the build output lines.
+	// The colors are enabled only when Stdout is a terminal and NO_COLOR is not set
+	NoColor bool
+	// Stdout is the writer for the progress messages and the commands output. Default to os.Stdout
+	Stdout io.Writer
+	// Stderr is the writer for the commands errors. Default to os.Stderr
+	Stderr io.Writer
+}
+
+// AllTargets is the key of the settings applied to all the targets, see Options.Cgo
+const AllTargets = "*"
+
+// Cgo represents the cgo compiler and linker settings of a target.
+// The ${APP_DIR} placeholder is replaced with the container path of the package root directory,
+// i.e. -I${APP_DIR}/third_party/include
+type Cgo struct {
+	// CFLAGS are the flags passed to the C compiler, exported as CGO_CFLAGS.
+	// They replace the go default flags, -g -O2
+	CFLAGS string
+	// LDFLAGS are the flags passed to the C linker, exported as CGO_LDFLAGS
+	LDFLAGS string
+	// PkgConfigPath is the list of the pkg-config folders separated by colon, exported as PKG_CONFIG_PATH
+	PkgConfigPath string
+}
+
+// Bundle represents the options to bundle the assets via fyne bundle
+type Bundle struct {
+	// Dir is the assets directory relative to the package root directory. Bundle is skipped if empty
+	Dir string
+	// Package is the package name of the bundled go file. Default to "main"
+	Package string
+	// Output is the bundled go file relative to the package root directory. Default to "bundled.go"
+	Output string
+}
+
+// Builder represents the docker builder
+type Builder struct {
+	targets []string
+	output  string
+	// pkg is the package the build steps run for, see forPackage
+	pkg string
+	// pkgs are the main packages to build
+	pkgs     []string
+	workDir  string
+	cacheDir string
+	verbose  bool
+	ldflags  string
+	// buildMode is the go build mode, see Options.BuildMode
+	buildMode string
+	// harden is true to build the linux targets with the hardening flags, see Options.Harden
+	harden bool
+	// windowsConsole is true to build the windows targets as console applications
+	windowsConsole bool
+	// cgo are the cgo compiler and linker settings by target, see Options.Cgo
+	cgo map[string]Cgo
+	// goarm is the ARM architecture version of the arm targets, if any
+	goarm string
+	// goamd64 is the microarchitecture level of the amd64 targets, if any
+	goamd64 string
+	force   bool
+	ccache  bool
+	// keepGoing is true to continue building the remaining targets on failure
+	keepGoing bool
+	sbom      bool
+	subdir    bool
+	bundle    Bundle
+	// icon is the source PNG icon relative to the work dir, if any
+	icon string
+	// packagers are the names of the packagers to run on the built artifacts
+	packagers []string
+	// publishers are the names of the publishers to run on the artifacts
+	publishers []string
+	// githubOutput is true to integrate with the GitHub Actions workflow log and step outputs
+	githubOutput bool
+	// image is the docker image used to run the containers. Empty means DefaultImage
+	image string
+	// cpus is the number of CPUs available to the containers, if limited
+	cpus string
+	// memory is the memory limit of the containers, if any
+	memory string
+	// network is the network of the build container, if not the default one
+	network string
+	// retries is the number of retries of the network dependent steps, see retry
+	retries int
+	// toolchain is the Go version of the toolchain downloaded into the cache volume.
+	// Empty means the Go version of the image
+	toolchain string
+	// aptPackages are the extra system packages to install into the image
+	aptPackages []string
+	// selinux is the SELinux label option for the volume mounts, if any
+	selinux string
+	// proxy represents the proxy settings forwarded into the container
+	proxy Proxy
+	// caCerts are the custom CA certificates to add to the container trust store
+	caCerts []string
+	// volumes are the additional volumes mounted into the container, see volumesArgs
+	volumes []volumeMount
+	// env are the KEY=VALUE env variables exported into the containers, see envArgs
+	env []string
+	// envFile is the temporary env file passed to docker, see writeEnvFile
+	envFile string
+	// gomod is true when the work dir contains a go.mod file
+	gomod bool
+	// importPath is the import path of the work dir when located under the host GOPATH
+	importPath string
+	// rootless is true when the container engine maps the container root user
+	// to the host user, i.e. podman or docker in rootless mode
+	rootless bool
+	// containerID is the ID of the container session
+	containerID string
+	// sessionLock is the shared lock held on the cache directory by the container session
+	sessionLock *fileLock
+	// uid is the user id used to run the commands into the container, see containerUser.
+	// Empty means the container root user
+	uid string
+	// gid is the group id used to run the commands into the container, see containerUser
+	gid string
+	// version is the version of the build, see Options.Version
+	version string
+	// deltaFrom is the absolute path of the previous release folder, see Options.DeltaFrom
+	deltaFrom string
+	// color is true to color the target prefixes of the build output lines, see targetPrefix
+	color bool
+	// timeout is the timeout of each build step, see withTimeout
+	timeout time.Duration
+	// session is the identifier of the containers run by the builder, see removeContainers
+	session string
+	// ctx is the context of the running operation, used to run the docker commands
+	ctx    context.Context
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// NewBuilder returns the builder configured with opts. Default values are used for the unset options
+func NewBuilder(opts Options) (*Builder, error) {
+	targets := opts.Targets
+	if len(targets) == 0 {
+		targets = []string{DefaultTarget()}
+	}
+	for _, target := range targets {
+		if _, ok := targetWithBuildOpts[target]; !ok {
+			return nil, fmt.Errorf("Unsupported target %q", target)
+		}
+	}
+
+	var err error
+	dir := opts.Dir
+	if dir == "" {
+		dir, err = os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("Cannot get the path for current directory %s", err)
+		}
+	}
+
+	workDir, err := resolveWorkDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheDir := opts.CacheDir
+	if cacheDir == "" {
+		cacheDir, err = os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("Cannot get the path for cache directory %s", err)
+		}
+	}
+
+	selinuxLabel := opts.SELinuxLabel
+	if selinuxLabel == "" {
+		selinuxLabel = "auto"
+	}
+	label, err := parseSELinuxLabel(selinuxLabel)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to parse selinux-label option %s", err)
+	}
+
+	certs, err := resolveCACerts(opts.CACerts)
+	if err != nil {
+		return nil, err
+	}
+
+	volumes, err := resolveVolumes(opts.Volumes)
+	if err != nil {
+		return nil, err
+	}
+
+	uid, gid, err := resolveUser(opts.User, opts.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	deltaFrom, err := resolveDeltaFrom(opts.DeltaFrom)
+	if err != nil {
+		return nil, err
+	}
+	if deltaFrom != "" && opts.Version == "" {
+		return nil, fmt.Errorf("The version option is required to generate the deltas from the previous release")
+	}
+
+	buildMode := opts.BuildMode
+	if buildMode == "" {
+		buildMode = "exe"
+	}
+	if _, ok := buildModeExt[buildMode]; !ok {
+		return nil, fmt.Errorf("Unsupported build mode %q, supported modes are exe, c-shared and c-archive", buildMode)
+	}
+
+	icon := ""
+	if opts.Icon != "" {
+		icon, err = checkIcon(workDir, opts.Icon)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	env, err := loadEnv(workDir, opts.EnvFile, opts.Env)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Harden {
+		err = checkHardeningLdflags(opts.Ldflags)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = checkMicroarchitecture(targets, "arm", "GOARM", opts.GOARM)
+	if err != nil {
+		return nil, err
+	}
+	err = checkMicroarchitecture(targets, "amd64", "GOAMD64", opts.GOAMD64)
+	if err != nil {
+		return nil, err
+	}
+
+	for target := range opts.Cgo {
+		if _, ok := targetWithBuildOpts[target]; !ok && target != AllTargets {
+			return nil, fmt.Errorf("Unsupported target %q for the cgo settings", target)
+		}
+	}
+
+	if opts.CPUs != "" {
+		cpus, err := strconv.ParseFloat(opts.CPUs, 64)
+		if err != nil || cpus <= 0 {
+			return nil, fmt.Errorf("Invalid CPUs %q, expected a positive number i.e. 1.5", opts.CPUs)
+		}
+	}
+	if opts.Memory != "" && !memoryRegexp.MatchString(opts.Memory) {
+		return nil, fmt.Errorf("Invalid memory %q, expected a positive integer with an optional b, k, m or g unit i.e. 4g", opts.Memory)
+	}
+
+	if opts.Retries < 0 {
+		return nil, fmt.Errorf("Invalid retries %d, expected zero or more", opts.Retries)
+	}
+
+	if opts.GoVersion != "" {
+		err = checkGoVersion(opts.GoVersion)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// GOAMD64 is ignored by the go releases before 1.18, i.e. the image one
+	if opts.GOAMD64 != "" && goMinorVersion(opts.GoVersion) < 18 {
+		return nil, fmt.Errorf("GOAMD64 requires Go 1.18 or later, set the Go version to download via the go option")
+	}
+
+	err = checkAptPackages(opts.AptPackages)
+	if err != nil {
+		return nil, err
+	}
+
+	// fail early on unknown plugins, before building
+	for _, name := range opts.Packagers {
+		_, err = LookupPackager(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range opts.Publishers {
+		_, err = LookupPublisher(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pkgs := opts.Packages
+	if len(pkgs) == 0 {
+		pkgs = []string{"."}
+	}
+	pkgs, err = expandPackages(workDir, pkgs)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) > 1 && opts.Output != "" {
+		return nil, fmt.Errorf("The output option cannot be used building multiple packages, the package name is used")
+	}
+	if len(pkgs) > 1 {
+		err = checkOutputNames(workDir, pkgs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	bundle := opts.Bundle
+	if bundle.Package == "" {
+		bundle.Package = "main"
+	}
+	if bundle.Output == "" {
+		bundle.Output = "bundled.go"
+	}
+
+	stdout := opts.Stdout
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+	color := !opts.NoColor && colorEnabled(stdout)
+	// the progress messages are written concurrently with the commands output, see step.
+	// Files are written as is to preserve the terminal detection of the commands
+	if _, ok := stdout.(*os.File); !ok {
+		stdout = &syncWriter{w: stdout}
+	}
+	stderr := opts.Stderr
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+
+	return &Builder{
+		pkg:            pkgs[0],
+		pkgs:           pkgs,
+		workDir:        workDir,
+		gomod:          hasGoMod(workDir),
+		importPath:     gopathImportPath(workDir),
+		cacheDir:       cacheDir,
+		targets:        targets,
+		output:         opts.Output,
+		verbose:        opts.Verbose,
+		ldflags:        opts.Ldflags,
+		buildMode:      buildMode,
+		harden:         opts.Harden,
+		windowsConsole: opts.WindowsConsole,
+		cgo:            opts.Cgo,
+		goarm:          opts.GOARM,
+		goamd64:        opts.GOAMD64,
+		force:          opts.Force,
+		keepGoing:      opts.KeepGoing,
+		ccache:         opts.CCache,
+		sbom:           opts.SBOM,
+		subdir:         opts.TargetSubdir,
+		selinux:        label,
+		caCerts:        certs,
+		volumes:        volumes,
+		uid:            uid,
+		gid:            gid,
+		version:        opts.Version,
+		deltaFrom:      deltaFrom,
+		env:            env,
+		proxy:          opts.Proxy,
+		bundle:         bundle,
+		icon:           icon,
+		image:          opts.Image,
+		toolchain:      opts.GoVersion,
+		retries:        opts.Retries,
+		cpus:           opts.CPUs,
+		memory:         opts.Memory,
+		network:        opts.Network,
+		aptPackages:    opts.AptPackages,
+		packagers:      opts.Packagers,
+		publishers:     opts.Publishers,
+		githubOutput:   opts.GitHubOutput,
+		stdout:         stdout,
+		color:          color,
+		timeout:        opts.Timeout,
+		session:        newSessionID(),
+		stderr:         stderr,
+	}, nil
+}
+
+// Build cross compiles the package with opts and returns the artifacts of the built targets.
+// It is a shortcut for NewBuilder followed by Builder.Build
+func Build(ctx context.Context, opts Options) ([]Artifact, error) {
+	b, err := NewBuilder(opts)
+	if err != nil {
+		return nil, err
+	}
+	return b.Build(ctx)
+}
+
+// Build cross compiles the package for all the targets into a container session
+// and returns the artifacts of the built targets, up-to-date targets included.
+// The build manifest is written into the build output folder.
+// In the GitHub output mode the returned error is also reported as error annotation
+func (d *Builder) Build(ctx context.Context) (artifacts []Artifact, err error) {
+	d.ctx = ctx
+	if d.githubOutput {
+		defer func() {
+			if err != nil {
+				d.printError(err)
+			}
+		}()
+	}
+
+	for _, pkg := range d.pkgs {
+		err = d.forPackage(pkg).preflight()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = d.checkRequirements()
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.writeEnvFile()
+	if err != nil {
+		return nil, err
+	}
+	defer d.removeEnvFile()
+
+	err = d.ensureImage()
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.start()
+	if err != nil {
+		return nil, fmt.Errorf("Cannot start the build container %s", err)
+	}
+	defer d.stop()
+
+	return d.build()
+}
+
+// build runs the build steps for all the targets into the container session
+func (d *Builder) build() ([]Artifact, error) {
+	if d.bundle.Dir != "" {
+		fmt.Fprintf(d.stdout, "Bundling assets from %s\n", d.bundle.Dir)
+		err := d.fyneBundle()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	start := time.Now()
+	err := d.downloadToolchain()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = d.step("Downloading dependencies", d.goGet)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.icon != "" {
+		d.startGroup(fmt.Sprintf("Generating the icons from %s", d.icon))
+		for _, pkg := range d.pkgs {
+			err = d.forPackage(pkg).generateIcons()
+			if err != nil {
+				break
+			}
+		}
+		d.endGroup()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	inputsHash, err := d.inputsHash()
+	if err != nil {
+		return nil, fmt.Errorf("Cannot compute the hash of the build inputs %s", err)
+	}
+	hashes := loadBuildHashes(d.hashesFile())
+
+	fmt.Fprintf(d.stdout, "Build output folder: %s/build\n", d.workDir)
+	results := []targetResult{}
+	built := []targetResult{}
+	failed := 0
+	total := len(d.targets) * len(d.pkgs)
+	for _, target := range d.targets {
+		for _, pkg := range d.pkgs {
+			progress := fmt.Sprintf("[%d/%d]", len(results)+1, total)
+			res := d.forPackage(pkg).buildTarget(target, progress, inputsHash, hashes)
+			results = append(results, res)
+			if res.err != nil {
+				if !d.keepGoing || d.canceled() {
+					return nil, res.err
+				}
+				d.printError(res.err)
+				failed++
+				continue
+			}
+			if res.status == statusSkipped {
+				continue
+			}
+			built = append(built, res)
+		}
+	}
+	if len(built) == 0 && failed == 0 {
+		return nil, fmt.Errorf("Nothing to build, the build constraints exclude all the targets")
+	}
+
+	err = d.chownOutput()
+	if err != nil {
+		return nil, fmt.Errorf("Cannot set the ownership of the build output folder %s", err)
+	}
+
+	artifacts, err := d.targetArtifacts(built)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.deltaFrom != "" {
+		deltas, err := d.generateDeltas(artifacts)
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, deltas...)
+	}
+
+	for _, name := range d.packagers {
+		d.startGroup(fmt.Sprintf("Packaging via %s", name))
+		packaged, err := d.runPackager(name, artifacts)
+		d.endGroup()
+		if err != nil {
+			return nil, fmt.Errorf("Packaging via %s failed %s", name, err)
+		}
+		artifacts = append(artifacts, packaged...)
+	}
+
+	err = d.writeManifest(artifacts)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot write the build manifest %s", err)
+	}
+	fmt.Fprintf(d.stdout, "Build manifest: %s/build/%s\n", d.workDir, manifestFile)
+
+	if d.githubOutput {
+		err = d.writeGitHubOutput(artifacts)
+		if err != nil {
+			return nil, fmt.Errorf("Cannot write the GitHub step outputs %s", err)
+		}
+	}
+
+	printSummary(d.stdout, results, time.Since(start))
+	if failed > 0 {
+		return artifacts, fmt.Errorf("Build failed for %d of %d targets", failed, len(results))
+	}
+
+	for _, name := range d.publishers {
+		d.startGroup(fmt.Sprintf("Publishing via %s", name))
+		err = d.runPublisher(name, artifacts)
+		d.endGroup()
+		if err != nil {
+			return artifacts, fmt.Errorf("Publishing via %s failed %s", name, err)
+		}
+	}
+	return artifacts, nil
+}
+
+// forPackage returns a copy of the builder running the build steps for pkg.
+// The container session is shared
+func (d *Builder) forPackage(pkg string) *Builder {
+	pd := *d
+	pd.pkg = pkg
+	return &pd
+}
+
+// buildTarget builds the target, unless up-to-date, and generates the SBOM if enabled.
+// The build hashes are updated on success
+func (d *Builder) buildTarget(target string, progress string, inputsHash string, hashes buildHashes) targetResult {
+	res := targetResult{target: target, pkg: d.pkg}
+
+	t, err := d.targetOutput(target)
+	if err != nil {
+		res.err = err
+		return res
+	}
+	res.output = t
+
+	err = d.checkTargetConstraints(target)
+	if err != nil {
+		fmt.Fprintf(d.stdout, "%s Warning: skipping %s, %s\n", progress, target, err)
+		res.status = statusSkipped
+		return res
+	}
+
+	targetHash, err := d.targetHash(target, inputsHash)
+	if err != nil {
+		res.err = err
+		return res
+	}
+	if !d.force && hashes[d.hashKey(target)] == targetHash && d.isBuilt(target) {
+		fmt.Fprintf(d.stdout, "%s Skipping %s, %s is up-to-date\n", progress, target, t)
+		res.status = statusUpToDate
+		return res
+	}
+
+	res.elapsed, err = d.step(fmt.Sprintf("%s Building for %s", progress, target), func() error {
+		err := d.goBuild(target)
+		if err != nil {
+			return fmt.Errorf("Build failed for %s %s", target, err)
+		}
+		fmt.Fprintf(d.stdout, "Built as %s\n", t)
+
+		if d.sbom {
+			err = d.writeSBOM(target)
+			if err != nil {
+				return fmt.Errorf("Cannot generate the SBOM for %s %s", target, err)
+			}
+			fmt.Fprintf(d.stdout, "SBOM written as %s%s\n", t, sbomExt)
+		}
+		return nil
+	})
+	if err != nil {
+		// remove the output possibly half-written on cancellation or timeout
+		os.Remove(filepath.Join(d.workDir, "build", t))
+		os.Remove(filepath.Join(d.workDir, "build", t+sbomExt))
+		res.err = err
+		return res
+	}
+
+	hashes[d.hashKey(target)] = targetHash
+	err = hashes.save(d.hashesFile())
+	if err != nil {
+		res.err = fmt.Errorf("Cannot save the hash of the build inputs %s", err)
+		return res
+	}
+
+	res.status = statusBuilt
+	return res
+}
+
+// outputName returns the name of the output file without the target suffix.
+// Default to the package name
+func (d *Builder) outputName() (string, error) {
+	if d.output != "" {
+		return d.output, nil
+	}
+	// the packages are named after their directory when building multiple packages, see checkOutputNames
+	if d.pkg != "." || len(d.pkgs) > 1 {
+		return packageDirName(d.workDir, d.pkg), nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(d.workDir, "*.go"))
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("Cannot found go files in %s", d.workDir)
+	}
+	return strings.TrimSuffix(filepath.Base(files[0]), ".go"), nil
+}
+
+// targetOutput returns the output file for the specified target relative to the build folder.
+// Default prefix is the package name. To override use the output option.
+// Example: fyne-linux-amd64 or linux-amd64/fyne-linux-amd64 when the target subdir option is set
+func (d *Builder) targetOutput(target string) (string, error) {
+	output, err := d.outputName()
+	if err != nil {
+		return "", err
+	}
+
+	normalizedTarget := strings.Replace(target, "/", "-", -1)
+
+	name := fmt.Sprintf("%s-%s%s", output, normalizedTarget, d.outputExt(target))
+	if d.subdir {
+		return normalizedTarget + "/" + name, nil
+	}
+	return name, nil
+}
+
+// isBuilt returns true if the target output, and the SBOM if enabled, exist into the build folder
+func (d *Builder) isBuilt(target string) bool {
+	t, err := d.targetOutput(target)
+	if err != nil {
+		return false
+	}
+
+	files := []string{t}
+	if d.sbom {
+		files = append(files, t+sbomExt)
+	}
+	for _, f := range files {
+		_, err = os.Stat(filepath.Join(d.workDir, "build", f))
+		if err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// verbosityFlag returns the string used to set verbosity with go commands
+// according to current setting
+func (d *Builder) verbosityFlag() string {
+	v := ""
+	if d.verbose {
+		v = "-v"
+	}
+	return v
+}
+
+// targetEnv returns the env variables used to compile for target
+func (d *Builder) targetEnv(target string) []string {
+	env := []string{
+		// enable CGO
+		"CGO_ENABLED=1",
+	}
+
+	// add default compile target options env variables
+	if buildOpts, ok := targetWithBuildOpts[target]; ok {
+		for _, o := range buildOpts {
+			// compile via ccache, if enabled
+			if d.ccache && strings.HasPrefix(o, "CC=") {
+				o = "CC=ccache " + strings.TrimPrefix(o, "CC=")
+			}
+			env = append(env, o)
+		}
+	}
+
+	// select the microarchitecture level, if any
+	switch goarch := strings.Split(target, "/")[1]; {
+	case goarch == "arm" && d.goarm != "":
+		env = append(env, "GOARM="+d.goarm)
+	case goarch == "amd64" && d.goamd64 != "":
+		env = append(env, "GOAMD64="+d.goamd64)
+	}
+
+	// add the cgo compiler and linker settings, if any
+	env = append(env, d.cgoEnv(target)...)
+
+	// store the ccache files into the cache volume
+	if d.ccache {
+		env = append(env, "CCACHE_DIR=/go/ccache")
+	}
+
+	// use a go build cache for target, reused across the builds
+	env = append(env, fmt.Sprintf("GOCACHE=/go/gocache/%s", strings.Replace(target, "/", "-", -1)))
+
+	// use the downloaded toolchain, if any
+	return append(env, d.goEnv()...)
+}
+
+// outputExt returns the extension of the output file for target according to the build mode
+func (d *Builder) outputExt(target string) string {
+	mode := d.buildMode
+	if mode == "" {
+		mode = "exe"
+	}
+	goos := strings.Split(target, "/")[0]
+	if ext, ok := buildModeExt[mode][goos]; ok {
+		return ext
+	}
+	return buildModeExt[mode]["*"]
+}
+
+// isLibrary returns true when the build mode produces a library instead of an executable
+func (d *Builder) isLibrary() bool {
+	return d.buildMode != "" && d.buildMode != "exe"
+}
+
+// cgoEnv returns the env variables of the cgo settings for target. The settings of all the targets
+// precede the ones of the target, while the pkg-config folders of the target are searched first
+func (d *Builder) cgoEnv(target string) []string {
+	all, t := d.cgo[AllTargets], d.cgo[target]
+
+	join := func(sep string, values ...string) string {
+		parts := []string{}
+		for _, v := range values {
+			if v != "" {
+				parts = append(parts, strings.Replace(v, "${APP_DIR}", d.appDir(), -1))
+			}
+		}
+		return strings.Join(parts, sep)
+	}
+
+	env := []string{}
+	if v := join(" ", all.CFLAGS, t.CFLAGS); v != "" {
+		env = append(env, "CGO_CFLAGS="+v)
+	}
+	if v := join(" ", all.LDFLAGS, t.LDFLAGS); v != "" {
+		env = append(env, "CGO_LDFLAGS="+v)
+	}
+	if v := join(":", t.PkgConfigPath, all.PkgConfigPath); v != "" {
+		env = append(env, "PKG_CONFIG_PATH="+v)
+	}
+	return env
+}
+
+// goBuildCmd returns the "go build" command for target
+func (d *Builder) goBuildCmd(target string) ([]string, error) {
+	// add go build command
+	buildCmd := []string{d.goCmd(), "build"}
+
+	// add build mode, if not the default one.
+	// Hardened linux executables are built as position independent executables
+	hardened := d.harden && strings.HasPrefix(target, "linux/")
+	if d.isLibrary() {
+		buildCmd = append(buildCmd, "-buildmode", d.buildMode)
+	} else if hardened {
+		buildCmd = append(buildCmd, "-buildmode", "pie")
+	}
+
+	// Start adding ldflags
+	ldflags := []string{}
+	// add defaults. They apply to executables only, i.e. the windows GUI subsystem,
+	// dropped for the windows console applications
+	consoleApp := d.windowsConsole && strings.HasPrefix(target, "windows/")
+	if ldflagsDefault, ok := targetLdflags[target]; ok && !d.isLibrary() && !consoleApp {
+		ldflags = append(ldflags, ldflagsDefault)
+	}
+	// add hardening flags
+	if hardened {
+		ldflags = append(ldflags, hardeningExtldflags)
+	}
+	// add custom ldflags
+	if d.ldflags != "" {
+		ldflags = append(ldflags, d.ldflags)
+	}
+
+	// add ldflags to command, if any
+	if len(ldflags) > 0 {
+		buildCmd = append(buildCmd, "-ldflags", strings.Join(ldflags, " "))
+	}
+
+	// add target output
+	targetOutput, err := d.targetOutput(target)
+	if err != nil {
+		return []string{}, err
+	}
+	buildCmd = append(buildCmd, "-o", fmt.Sprintf("build/%s", targetOutput))
+
+	// add force compile option
+	if d.force {
+		buildCmd = append(buildCmd, "-a")
+	}
+
+	// add verbosity option
+	if d.verbose {
+		buildCmd = append(buildCmd, "-v")
+	}
+
+	// add package
+	buildCmd = append(buildCmd, d.pkg)
+
+	return buildCmd, nil
+}
+
+// checkHardeningLdflags returns an error if the custom ldflags set the external linker flags,
+// since the last -extldflags passed to the go linker replaces the hardening ones
+func checkHardeningLdflags(ldflags string) error {
+	for _, flag := range strings.Fields(ldflags) {
+		if flag == "-extldflags" || strings.HasPrefix(flag, "-extldflags=") {
+			return fmt.Errorf("The harden option cannot be used with custom -extldflags, they would replace the hardening flags %s", hardeningExtldflags)
+		}
+	}
+	return nil
+}
+
+// checkMicroarchitecture checks the value of the env variable name selecting the microarchitecture
+// level of goarch. The level is valid only when at least a goarch target is selected
+func checkMicroarchitecture(targets []string, goarch string, name string, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	valid := false
+	for _, level := range microarchitectureLevels[name] {
+		if value == level {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("Invalid %s %q, supported values are %s", name, value, strings.Join(microarchitectureLevels[name], ", "))
+	}
+
+	for _, target := range targets {
+		if strings.HasSuffix(target, "/"+goarch) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s applies to the %s targets only, none is selected", name, goarch)
+}
+
+// parseSELinuxLabel parses the SELinux label option. When set to auto
+// the shared label is used if SELinux is enforcing on the host
+func parseSELinuxLabel(label string) (string, error) {
+	switch label {
+	case "z", "Z":
+		return label, nil
+	case "none":
+		return "", nil
+	case "auto":
+		b, err := ioutil.ReadFile("/sys/fs/selinux/enforce")
+		if err == nil && strings.TrimSpace(string(b)) == "1" {
+			return "z", nil
+		}
+		return "", nil
+	}
+	return "", fmt.Errorf("Unsupported SELinux label %q", label)
+}
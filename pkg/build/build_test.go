@@ -0,0 +1,1111 @@
+// Run a command line helper for various Fyne tools.
+package build
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_parseSELinuxLabel(t *testing.T) {
+	tests := []struct {
+		name    string
+		label   string
+		want    string
+		wantErr bool
+	}{
+		{name: "shared", label: "z", want: "z"},
+		{name: "private", label: "Z", want: "Z"},
+		{name: "none", label: "none", want: ""},
+		{name: "invalid", label: "x", want: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSELinuxLabel(tt.label)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseSELinuxLabel() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("parseSELinuxLabel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTargets(t *testing.T) {
+	type args struct {
+		targetList string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "Target list cannot be empty",
+			args:    args{targetList: ""},
+			want:    []string{},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid target",
+			args:    args{targetList: "invalid/amd64"},
+			want:    []string{},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid target 2",
+			args:    args{targetList: "linux/amd64,invalid/amd64"},
+			want:    []string{"linux/amd64"},
+			wantErr: true,
+		},
+		{
+			name:    "Valid target",
+			args:    args{targetList: "linux/amd64"},
+			want:    []string{"linux/amd64"},
+			wantErr: false,
+		},
+		{
+			name:    "Valid targets trim space",
+			args:    args{targetList: "linux/amd64, darwin/386"},
+			want:    []string{"linux/amd64", "darwin/386"},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTargets(tt.args.targetList)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseTargets() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTargets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuilder_targetOutput(t *testing.T) {
+	type fields struct {
+		output    string
+		pkg       string
+		subdir    bool
+		buildMode string
+	}
+	type args struct {
+		target string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "default *nix plaform",
+			fields: fields{
+				output: "",
+				pkg:    "fyne-io/fyne-example",
+			},
+			args: args{
+				target: "linux/amd64",
+			},
+			want: "fyne-example-linux-amd64",
+		},
+		{
+			name: "default windows plaform",
+			fields: fields{
+				output: "",
+				pkg:    "fyne-io/fyne-example",
+			},
+			args: args{
+				target: "windows/386",
+			},
+			want: "fyne-example-windows-386.exe",
+		},
+		{
+			name: "custom output *nix plaform",
+			fields: fields{
+				output: "test",
+				pkg:    "fyne-io/fyne-example",
+			},
+			args: args{
+				target: "linux/amd64",
+			},
+			want: "test-linux-amd64",
+		},
+		{
+			name: "custom output windows plaform",
+			fields: fields{
+				output: "test",
+				pkg:    "fyne-io/fyne-example",
+			},
+			args: args{
+				target: "windows/386",
+			},
+			want: "test-windows-386.exe",
+		},
+		{
+			name: "target subdir *nix plaform",
+			fields: fields{
+				output: "",
+				pkg:    "fyne-io/fyne-example",
+				subdir: true,
+			},
+			args: args{
+				target: "linux/amd64",
+			},
+			want: "linux-amd64/fyne-example-linux-amd64",
+		},
+		{
+			name: "target subdir windows plaform",
+			fields: fields{
+				output: "test",
+				pkg:    "fyne-io/fyne-example",
+				subdir: true,
+			},
+			args: args{
+				target: "windows/386",
+			},
+			want: "windows-386/test-windows-386.exe",
+		},
+		{
+			name: "c-shared windows plaform",
+			fields: fields{
+				pkg:       "fyne-io/fyne-example",
+				buildMode: "c-shared",
+			},
+			args: args{
+				target: "windows/amd64",
+			},
+			want: "fyne-example-windows-amd64.dll",
+		},
+		{
+			name: "c-shared darwin plaform",
+			fields: fields{
+				pkg:       "fyne-io/fyne-example",
+				buildMode: "c-shared",
+			},
+			args: args{
+				target: "darwin/amd64",
+			},
+			want: "fyne-example-darwin-amd64.dylib",
+		},
+		{
+			name: "c-shared linux plaform",
+			fields: fields{
+				pkg:       "fyne-io/fyne-example",
+				buildMode: "c-shared",
+			},
+			args: args{
+				target: "linux/amd64",
+			},
+			want: "fyne-example-linux-amd64.so",
+		},
+		{
+			name: "c-archive windows plaform",
+			fields: fields{
+				pkg:       "fyne-io/fyne-example",
+				buildMode: "c-archive",
+			},
+			args: args{
+				target: "windows/386",
+			},
+			want: "fyne-example-windows-386.a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Builder{
+				output:    tt.fields.output,
+				pkg:       tt.fields.pkg,
+				subdir:    tt.fields.subdir,
+				buildMode: tt.fields.buildMode,
+			}
+			got, err := d.targetOutput(tt.args.target)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Builder.targetOutput() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Builder.targetOutput() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuilder_outputName_workDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fyne-cross")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	d := &Builder{pkg: ".", workDir: dir}
+	_, err = d.outputName()
+	if err == nil {
+		t.Errorf("Builder.outputName() expected error for a work dir without go files")
+	}
+
+	// the work dir differs from the current dir
+	err = ioutil.WriteFile(filepath.Join(dir, "example.go"), []byte("package main"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := d.outputName()
+	if err != nil {
+		t.Fatalf("Builder.outputName() error = %v", err)
+	}
+	if got != "example" {
+		t.Errorf("Builder.outputName() = %v, want %v", got, "example")
+	}
+
+	// the current package is named after the work dir when building multiple packages
+	d.pkgs = []string{".", "./cmd/app"}
+	got, err = d.outputName()
+	if err != nil {
+		t.Fatalf("Builder.outputName() error = %v", err)
+	}
+	if want := filepath.Base(dir); got != want {
+		t.Errorf("Builder.outputName() = %v, want %v", got, want)
+	}
+}
+
+func TestBuilder_verbosityFlag(t *testing.T) {
+	type fields struct {
+		verbose bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   string
+	}{
+		{
+			name: "verbosity enabled",
+			fields: fields{
+				verbose: true,
+			},
+			want: "-v",
+		},
+		{
+			name: "verbosity disabled",
+			fields: fields{
+				verbose: false,
+			},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Builder{
+				verbose: tt.fields.verbose,
+			}
+			if got := d.verbosityFlag(); got != tt.want {
+				t.Errorf("Builder.verbosityFlag() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuilder_defaultArgs(t *testing.T) {
+	// current work dir
+	wd, _ := os.Getwd()
+
+	// cache dir
+	cd, _ := os.UserCacheDir()
+
+	type fields struct {
+		pkg      string
+		workDir  string
+		cacheDir string
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   []string
+	}{
+		{
+			name: "default workdir",
+			fields: fields{
+				pkg:      "fyne-io/fyne-example",
+				workDir:  wd,
+				cacheDir: cd,
+			},
+			want: []string{
+				"run", "--rm",
+				"-w", "/app",
+				"-v", wd + ":/app",
+				"-v", cd + "/fyne-cross:/go",
+				"-e", "fyne_user=1000:1000",
+			},
+		},
+		{
+			name: "custom workdir",
+			fields: fields{
+				pkg:      "fyne-io/fyne-example",
+				workDir:  "/home/fyne",
+				cacheDir: "/tmp/cache",
+			},
+			want: []string{
+				"run", "--rm",
+				"-w", "/app",
+				"-v", "/home/fyne:/app",
+				"-v", "/tmp/cache/fyne-cross:/go",
+				"-e", "fyne_user=1000:1000",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Builder{
+				pkg:      tt.fields.pkg,
+				workDir:  tt.fields.workDir,
+				cacheDir: tt.fields.cacheDir,
+				uid:      "1000",
+				gid:      "1000",
+			}
+			if got := d.defaultArgs(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Builder.defaultArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuilder_defaultArgs_rootless(t *testing.T) {
+	d := &Builder{
+		workDir:  "/home/fyne",
+		cacheDir: "/tmp/cache",
+		rootless: true,
+	}
+	want := []string{
+		"run", "--rm",
+		"-w", "/app",
+		"-v", "/home/fyne:/app",
+		"-v", "/tmp/cache/fyne-cross:/go",
+	}
+	if got := d.defaultArgs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Builder.defaultArgs() = %v, want %v", got, want)
+	}
+}
+
+func TestBuilder_sessionArgs(t *testing.T) {
+	d := &Builder{
+		workDir:  "/home/fyne",
+		cacheDir: "/tmp/cache",
+		rootless: true,
+	}
+	want := []string{
+		"run", "--rm",
+		"-w", "/app",
+		"-v", "/home/fyne:/app",
+		"-v", "/tmp/cache/fyne-cross:/go",
+		"-d",
+		"-v", "/tmp/cache/fyne-cross/pkg/mod:/go/pkg/mod:ro",
+	}
+	if got := d.sessionArgs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Builder.sessionArgs() = %v, want %v", got, want)
+	}
+}
+
+func TestBuilder_sessionArgs_limits(t *testing.T) {
+	d := &Builder{
+		workDir:  "/home/fyne",
+		cacheDir: "/tmp/cache",
+		rootless: true,
+		cpus:     "1.5",
+		memory:   "4g",
+		network:  "none",
+	}
+	want := []string{
+		"run", "--rm",
+		"-w", "/app",
+		"-v", "/home/fyne:/app",
+		"-v", "/tmp/cache/fyne-cross:/go",
+		"--cpus", "1.5",
+		"--memory", "4g",
+		"-d",
+		"--network", "none",
+		"-v", "/tmp/cache/fyne-cross/pkg/mod:/go/pkg/mod:ro",
+	}
+	if got := d.sessionArgs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Builder.sessionArgs() = %v, want %v", got, want)
+	}
+
+	// the dependencies are downloaded with network access
+	for _, got := range d.goGetArgs() {
+		if containsArg(got, "--network") {
+			t.Errorf("Builder.goGetArgs() = %v, want no network option", got)
+		}
+	}
+}
+
+// containsArg returns true if args contains arg
+func containsArg(args []string, arg string) bool {
+	for _, a := range args {
+		if a == arg {
+			return true
+		}
+	}
+	return false
+}
+
+func TestBuilder_volume(t *testing.T) {
+	type args struct {
+		hostPath      string
+		containerPath string
+		opts          []string
+	}
+	tests := []struct {
+		name    string
+		selinux string
+		args    args
+		want    string
+	}{
+		{
+			name: "no options",
+			args: args{hostPath: "/home/fyne", containerPath: "/app"},
+			want: "/home/fyne:/app",
+		},
+		{
+			name: "read-only",
+			args: args{hostPath: "/home/fyne", containerPath: "/app", opts: []string{"ro"}},
+			want: "/home/fyne:/app:ro",
+		},
+		{
+			name:    "selinux label",
+			selinux: "z",
+			args:    args{hostPath: "/home/fyne", containerPath: "/app"},
+			want:    "/home/fyne:/app:z",
+		},
+		{
+			name:    "read-only and selinux label",
+			selinux: "Z",
+			args:    args{hostPath: "/home/fyne", containerPath: "/app", opts: []string{"ro"}},
+			want:    "/home/fyne:/app:ro,Z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Builder{
+				selinux: tt.selinux,
+			}
+			if got := d.volume(tt.args.hostPath, tt.args.containerPath, tt.args.opts...); got != tt.want {
+				t.Errorf("Builder.volume() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuilder_chownArgs(t *testing.T) {
+	d := &Builder{
+		workDir:     "/home/fyne",
+		containerID: "fyne-cross",
+	}
+	want := []string{
+		"exec", "fyne-cross",
+		"chown", "-R", "1000:1000", "/app/build",
+	}
+	if got := d.chownArgs("1000", "1000"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Builder.chownArgs() = %v, want %v", got, want)
+	}
+}
+
+func TestBuilder_fyneBundleArgs(t *testing.T) {
+	d := &Builder{
+		containerID: "fyne-cross",
+		uid:         "1000",
+		bundle: Bundle{
+			Dir:     "assets",
+			Package: "main",
+			Output:  "bundled.go",
+		},
+	}
+	want := []string{
+		"exec", "-u", "1000", "-e", "HOME=/tmp/fyne-home",
+		"fyne-cross",
+		"fyne", "bundle", "-package", "main", "assets",
+	}
+	if got := d.fyneBundleArgs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Builder.fyneBundleArgs() = %v, want %v", got, want)
+	}
+}
+
+func TestBuilder_goGetArgs(t *testing.T) {
+	type fields struct {
+		verbose   bool
+		gomod     bool
+		targets   []string
+		toolchain string
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   [][]string
+	}{
+		{
+			name: "module mode",
+			fields: fields{
+				gomod:   true,
+				targets: []string{"linux/amd64", "windows/amd64"},
+			},
+			want: [][]string{
+				{
+					"run", "--rm",
+					"-w", "/app",
+					"-v", "/home/fyne:/app",
+					"-v", "/tmp/cache/fyne-cross:/go",
+					"-e", "GO111MODULE=on",
+					"-t", DefaultImage, "go", "mod", "download",
+				},
+			},
+		},
+		{
+			name: "module mode, verbosity enabled",
+			fields: fields{
+				gomod:     true,
+				verbose:   true,
+				targets:   []string{"linux/amd64"},
+				toolchain: "1.18",
+			},
+			want: [][]string{
+				{
+					"run", "--rm",
+					"-w", "/app",
+					"-v", "/home/fyne:/app",
+					"-v", "/tmp/cache/fyne-cross:/go",
+					"-e", "GO111MODULE=on",
+					"-e", "GOROOT=/go/toolchains/go1.18",
+					"-t", DefaultImage, "/go/toolchains/go1.18/bin/go", "mod", "download", "-x",
+				},
+			},
+		},
+		{
+			name: "gopath mode",
+			fields: fields{
+				gomod:   false,
+				verbose: true,
+				targets: []string{"linux/amd64", "darwin/amd64"},
+			},
+			want: [][]string{
+				{
+					"run", "--rm",
+					"-w", "/app",
+					"-v", "/home/fyne:/app",
+					"-v", "/tmp/cache/fyne-cross:/go",
+					"-e", "GOOS=linux", "-e", "GOARCH=amd64", "-e", "CGO_ENABLED=1",
+					"-t", DefaultImage, "go", "get", "-v", "-d", "./...",
+				},
+				{
+					"run", "--rm",
+					"-w", "/app",
+					"-v", "/home/fyne:/app",
+					"-v", "/tmp/cache/fyne-cross:/go",
+					"-e", "GOOS=darwin", "-e", "GOARCH=amd64", "-e", "CGO_ENABLED=1",
+					"-t", DefaultImage, "go", "get", "-v", "-d", "./...",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Builder{
+				verbose:   tt.fields.verbose,
+				gomod:     tt.fields.gomod,
+				targets:   tt.fields.targets,
+				toolchain: tt.fields.toolchain,
+				workDir:   "/home/fyne",
+				cacheDir:  "/tmp/cache",
+				rootless:  true,
+			}
+			if got := d.goGetArgs(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Builder.goGetArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+func TestBuilder_goBuildArgs(t *testing.T) {
+	type fields struct {
+		targets     []string
+		output      string
+		pkg         string
+		workDir     string
+		verbose     bool
+		ldflags     string
+		buildMode   string
+		harden      bool
+		goarm       string
+		goamd64     string
+		console     bool
+		force       bool
+		ccache      bool
+		containerID string
+		uid         string
+	}
+	type args struct {
+		target string
+	}
+
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "verbosity enabled, linux",
+			fields: fields{
+				verbose:     true,
+				pkg:         "fyne-io/fyne-example",
+				workDir:     "/code/test",
+				output:      "test",
+				containerID: "fyne-cross",
+				uid:         "1000",
+			},
+			args: args{
+				target: "linux/amd64",
+			},
+			want: []string{
+				"exec", "-u", "1000", "-e", "HOME=/tmp/fyne-home",
+				"-e", "CGO_ENABLED=1",
+				"-e", "GOOS=linux", "-e", "GOARCH=amd64", "-e", "CC=gcc",
+				"-e", "GOCACHE=/go/gocache/linux-amd64",
+				"fyne-cross",
+				"go", "build", "-o", "build/test-linux-amd64", "-v", "fyne-io/fyne-example",
+			},
+		},
+		{
+			name: "verbosity disabled, windows",
+			fields: fields{
+				verbose:     false,
+				pkg:         "fyne-io/fyne-example",
+				workDir:     "/code/test",
+				output:      "test",
+				ldflags:     "-X main.version=1.0.0",
+				containerID: "fyne-cross",
+			},
+			args: args{
+				target: "windows/amd64",
+			},
+			want: []string{
+				"exec",
+				"-e", "CGO_ENABLED=1",
+				"-e", "GOOS=windows", "-e", "GOARCH=amd64", "-e", "CC=x86_64-w64-mingw32-gcc",
+				"-e", "GOCACHE=/go/gocache/windows-amd64",
+				"fyne-cross",
+				"go", "build", "-ldflags", "-H windowsgui -X main.version=1.0.0", "-o", "build/test-windows-amd64.exe", "fyne-io/fyne-example",
+			},
+		},
+		{
+			name: "default settings from current dir darwin",
+			fields: fields{
+				pkg:         "fyne-io/fyne-example",
+				containerID: "fyne-cross",
+			},
+			args: args{
+				target: "darwin/amd64",
+			},
+			want: []string{
+				"exec",
+				"-e", "CGO_ENABLED=1",
+				"-e", "GOOS=darwin", "-e", "GOARCH=amd64", "-e", "CC=o32-clang",
+				"-e", "GOCACHE=/go/gocache/darwin-amd64",
+				"fyne-cross",
+				"go", "build", "-o", "build/fyne-example-darwin-amd64", "fyne-io/fyne-example",
+			},
+		},
+		{
+			name: "ldflags, linux",
+			fields: fields{
+				verbose:     true,
+				pkg:         "fyne-io/fyne-example",
+				workDir:     "/code/test",
+				output:      "test",
+				ldflags:     "-X main.version=1.0.0",
+				containerID: "fyne-cross",
+			},
+			args: args{
+				target: "linux/amd64",
+			},
+			want: []string{
+				"exec",
+				"-e", "CGO_ENABLED=1",
+				"-e", "GOOS=linux", "-e", "GOARCH=amd64", "-e", "CC=gcc",
+				"-e", "GOCACHE=/go/gocache/linux-amd64",
+				"fyne-cross",
+				"go", "build", "-ldflags", "-X main.version=1.0.0", "-o", "build/test-linux-amd64", "-v", "fyne-io/fyne-example",
+			},
+		},
+		{
+			name: "ldflags with spaces and quotes, linux",
+			fields: fields{
+				pkg:         "fyne-io/fyne-example",
+				output:      "test",
+				ldflags:     "-X 'main.name=My App'",
+				containerID: "fyne-cross",
+			},
+			args: args{
+				target: "linux/amd64",
+			},
+			want: []string{
+				"exec",
+				"-e", "CGO_ENABLED=1",
+				"-e", "GOOS=linux", "-e", "GOARCH=amd64", "-e", "CC=gcc",
+				"-e", "GOCACHE=/go/gocache/linux-amd64",
+				"fyne-cross",
+				"go", "build", "-ldflags", "-X 'main.name=My App'", "-o", "build/test-linux-amd64", "fyne-io/fyne-example",
+			},
+		},
+		{
+			name: "force, linux",
+			fields: fields{
+				pkg:         "fyne-io/fyne-example",
+				output:      "test",
+				force:       true,
+				containerID: "fyne-cross",
+			},
+			args: args{
+				target: "linux/amd64",
+			},
+			want: []string{
+				"exec",
+				"-e", "CGO_ENABLED=1",
+				"-e", "GOOS=linux", "-e", "GOARCH=amd64", "-e", "CC=gcc",
+				"-e", "GOCACHE=/go/gocache/linux-amd64",
+				"fyne-cross",
+				"go", "build", "-o", "build/test-linux-amd64", "-a", "fyne-io/fyne-example",
+			},
+		},
+		{
+			name: "ccache, windows",
+			fields: fields{
+				pkg:         "fyne-io/fyne-example",
+				output:      "test",
+				ccache:      true,
+				containerID: "fyne-cross",
+			},
+			args: args{
+				target: "windows/386",
+			},
+			want: []string{
+				"exec",
+				"-e", "CGO_ENABLED=1",
+				"-e", "GOOS=windows", "-e", "GOARCH=386", "-e", "CC=ccache x86_64-w64-mingw32-gcc",
+				"-e", "CCACHE_DIR=/go/ccache",
+				"-e", "GOCACHE=/go/gocache/windows-386",
+				"fyne-cross",
+				"go", "build", "-ldflags", "-H windowsgui", "-o", "build/test-windows-386.exe", "fyne-io/fyne-example",
+			},
+		},
+		{
+			name: "c-shared, windows",
+			fields: fields{
+				pkg:         "fyne-io/fyne-example",
+				output:      "test",
+				ldflags:     "-X main.version=1.0.0",
+				buildMode:   "c-shared",
+				containerID: "fyne-cross",
+			},
+			args: args{
+				target: "windows/amd64",
+			},
+			want: []string{
+				"exec",
+				"-e", "CGO_ENABLED=1",
+				"-e", "GOOS=windows", "-e", "GOARCH=amd64", "-e", "CC=x86_64-w64-mingw32-gcc",
+				"-e", "GOCACHE=/go/gocache/windows-amd64",
+				"fyne-cross",
+				"go", "build", "-buildmode", "c-shared", "-ldflags", "-X main.version=1.0.0", "-o", "build/test-windows-amd64.dll", "fyne-io/fyne-example",
+			},
+		},
+		{
+			name: "hardened, linux",
+			fields: fields{
+				pkg:         "fyne-io/fyne-example",
+				output:      "test",
+				ldflags:     "-X main.version=1.0.0",
+				harden:      true,
+				containerID: "fyne-cross",
+			},
+			args: args{
+				target: "linux/amd64",
+			},
+			want: []string{
+				"exec",
+				"-e", "CGO_ENABLED=1",
+				"-e", "GOOS=linux", "-e", "GOARCH=amd64", "-e", "CC=gcc",
+				"-e", "GOCACHE=/go/gocache/linux-amd64",
+				"fyne-cross",
+				"go", "build", "-buildmode", "pie", "-ldflags", "-extldflags '-Wl,-z,relro -Wl,-z,now' -X main.version=1.0.0", "-o", "build/test-linux-amd64", "fyne-io/fyne-example",
+			},
+		},
+		{
+			name: "goamd64, linux",
+			fields: fields{
+				pkg:         "fyne-io/fyne-example",
+				output:      "test",
+				goamd64:     "v3",
+				containerID: "fyne-cross",
+			},
+			args: args{
+				target: "linux/amd64",
+			},
+			want: []string{
+				"exec",
+				"-e", "CGO_ENABLED=1",
+				"-e", "GOOS=linux", "-e", "GOARCH=amd64", "-e", "CC=gcc",
+				"-e", "GOAMD64=v3",
+				"-e", "GOCACHE=/go/gocache/linux-amd64",
+				"fyne-cross",
+				"go", "build", "-o", "build/test-linux-amd64", "fyne-io/fyne-example",
+			},
+		},
+		{
+			name: "goamd64, linux 386",
+			fields: fields{
+				pkg:         "fyne-io/fyne-example",
+				output:      "test",
+				goamd64:     "v3",
+				containerID: "fyne-cross",
+			},
+			args: args{
+				target: "linux/386",
+			},
+			want: []string{
+				"exec",
+				"-e", "CGO_ENABLED=1",
+				"-e", "GOOS=linux", "-e", "GOARCH=386", "-e", "CC=gcc",
+				"-e", "GOCACHE=/go/gocache/linux-386",
+				"fyne-cross",
+				"go", "build", "-o", "build/test-linux-386", "fyne-io/fyne-example",
+			},
+		},
+		{
+			name: "goarm, linux arm",
+			fields: fields{
+				pkg:         "fyne-io/fyne-example",
+				output:      "test",
+				goarm:       "6",
+				goamd64:     "v3",
+				containerID: "fyne-cross",
+			},
+			args: args{
+				target: "linux/arm",
+			},
+			want: []string{
+				"exec",
+				"-e", "CGO_ENABLED=1",
+				"-e", "GOOS=linux", "-e", "GOARCH=arm", "-e", "CC=arm-linux-gnueabihf-gcc",
+				"-e", "GOARM=6",
+				"-e", "GOCACHE=/go/gocache/linux-arm",
+				"fyne-cross",
+				"go", "build", "-o", "build/test-linux-arm", "fyne-io/fyne-example",
+			},
+		},
+		{
+			name: "console, windows",
+			fields: fields{
+				pkg:         "fyne-io/fyne-example",
+				output:      "test",
+				ldflags:     "-X main.version=1.0.0",
+				console:     true,
+				containerID: "fyne-cross",
+			},
+			args: args{
+				target: "windows/386",
+			},
+			want: []string{
+				"exec",
+				"-e", "CGO_ENABLED=1",
+				"-e", "GOOS=windows", "-e", "GOARCH=386", "-e", "CC=x86_64-w64-mingw32-gcc",
+				"-e", "GOCACHE=/go/gocache/windows-386",
+				"fyne-cross",
+				"go", "build", "-ldflags", "-X main.version=1.0.0", "-o", "build/test-windows-386.exe", "fyne-io/fyne-example",
+			},
+		},
+		{
+			name: "hardened, windows",
+			fields: fields{
+				pkg:         "fyne-io/fyne-example",
+				output:      "test",
+				harden:      true,
+				containerID: "fyne-cross",
+			},
+			args: args{
+				target: "windows/amd64",
+			},
+			want: []string{
+				"exec",
+				"-e", "CGO_ENABLED=1",
+				"-e", "GOOS=windows", "-e", "GOARCH=amd64", "-e", "CC=x86_64-w64-mingw32-gcc",
+				"-e", "GOCACHE=/go/gocache/windows-amd64",
+				"fyne-cross",
+				"go", "build", "-ldflags", "-H windowsgui", "-o", "build/test-windows-amd64.exe", "fyne-io/fyne-example",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Builder{
+				targets:        tt.fields.targets,
+				output:         tt.fields.output,
+				pkg:            tt.fields.pkg,
+				workDir:        tt.fields.workDir,
+				verbose:        tt.fields.verbose,
+				ldflags:        tt.fields.ldflags,
+				buildMode:      tt.fields.buildMode,
+				harden:         tt.fields.harden,
+				goarm:          tt.fields.goarm,
+				goamd64:        tt.fields.goamd64,
+				windowsConsole: tt.fields.console,
+				force:          tt.fields.force,
+				ccache:         tt.fields.ccache,
+				containerID:    tt.fields.containerID,
+				uid:            tt.fields.uid,
+			}
+			got, err := d.goBuildArgs(tt.args.target)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Builder.goBuildArgs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Builder.goBuildArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_checkHardeningLdflags(t *testing.T) {
+	tests := []struct {
+		name    string
+		ldflags string
+		wantErr bool
+	}{
+		{name: "no ldflags"},
+		{name: "custom ldflags", ldflags: "-s -w -X main.version=1.0.0"},
+		{name: "extldflags", ldflags: "-s -extldflags '-static'", wantErr: true},
+		{name: "extldflags with equal", ldflags: "-extldflags=-static", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkHardeningLdflags(tt.ldflags)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkHardeningLdflags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkMicroarchitecture(t *testing.T) {
+	tests := []struct {
+		name    string
+		targets []string
+		goarch  string
+		env     string
+		value   string
+		wantErr bool
+	}{
+		{name: "unset", targets: []string{"linux/386"}, goarch: "amd64", env: "GOAMD64"},
+		{name: "amd64 level", targets: []string{"linux/386", "windows/amd64"}, goarch: "amd64", env: "GOAMD64", value: "v3"},
+		{name: "invalid amd64 level", targets: []string{"linux/amd64"}, goarch: "amd64", env: "GOAMD64", value: "v5", wantErr: true},
+		{name: "no amd64 targets", targets: []string{"linux/386"}, goarch: "amd64", env: "GOAMD64", value: "v2", wantErr: true},
+		{name: "arm version", targets: []string{"linux/amd64", "linux/arm"}, goarch: "arm", env: "GOARM", value: "6"},
+		{name: "no arm targets", targets: []string{"linux/amd64"}, goarch: "arm", env: "GOARM", value: "7", wantErr: true},
+		{name: "invalid arm version", targets: []string{"linux/arm"}, goarch: "arm", env: "GOARM", value: "8", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkMicroarchitecture(tt.targets, tt.goarch, tt.env, tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("checkMicroarchitecture() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewBuilder_goamd64(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fyne-cross")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name      string
+		goVersion string
+		wantErr   bool
+	}{
+		{name: "image go", wantErr: true},
+		{name: "go before 1.18", goVersion: "1.17.5", wantErr: true},
+		{name: "go 1.18", goVersion: "1.18"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewBuilder(Options{
+				Dir:       dir,
+				Targets:   []string{"linux/amd64"},
+				GOAMD64:   "v3",
+				GoVersion: tt.goVersion,
+				CacheDir:  dir,
+			})
+			gotErr := err != nil && strings.Contains(err.Error(), "GOAMD64")
+			if gotErr != tt.wantErr {
+				t.Errorf("NewBuilder() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuilder_cgoEnv(t *testing.T) {
+	d := &Builder{
+		gomod: true,
+		cgo: map[string]Cgo{
+			AllTargets: {
+				CFLAGS:        "-O2 -I${APP_DIR}/third_party/include",
+				PkgConfigPath: "${APP_DIR}/third_party/pkgconfig",
+			},
+			"windows/amd64": {
+				CFLAGS:        "-DWIN64",
+				LDFLAGS:       "-L${APP_DIR}/third_party/win64 -lfoo",
+				PkgConfigPath: "/usr/x86_64-w64-mingw32/lib/pkgconfig",
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		want   []string
+	}{
+		{
+			name:   "all targets",
+			target: "linux/amd64",
+			want: []string{
+				"CGO_CFLAGS=-O2 -I/app/third_party/include",
+				"PKG_CONFIG_PATH=/app/third_party/pkgconfig",
+			},
+		},
+		{
+			name:   "target settings",
+			target: "windows/amd64",
+			want: []string{
+				"CGO_CFLAGS=-O2 -I/app/third_party/include -DWIN64",
+				"CGO_LDFLAGS=-L/app/third_party/win64 -lfoo",
+				"PKG_CONFIG_PATH=/usr/x86_64-w64-mingw32/lib/pkgconfig:/app/third_party/pkgconfig",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.cgoEnv(tt.target); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Builder.cgoEnv() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package build
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// caCertsDir is the container directory where the custom CA certificates are mounted.
+// The docker entrypoint adds them to the trust store on startup
+const caCertsDir = "/usr/local/share/ca-certificates/fyne-cross"
+
+// resolveCACerts validates the CA certificate files and returns their absolute paths
+func resolveCACerts(certs []string) ([]string, error) {
+	resolved := []string{}
+	for _, cert := range certs {
+		abs, err := filepath.Abs(cert)
+		if err != nil {
+			return nil, err
+		}
+		info, err := os.Stat(abs)
+		if err != nil {
+			return nil, fmt.Errorf("Cannot find the CA certificate %s", err)
+		}
+		if info.IsDir() {
+			return nil, fmt.Errorf("The CA certificate %q is a directory", abs)
+		}
+		resolved = append(resolved, abs)
+	}
+	return resolved, nil
+}
+
+// caCertsArgs returns the arguments used to mount the custom CA certificates into the container.
+// Files are renamed with the .crt extension as required by update-ca-certificates
+func (d *Builder) caCertsArgs() []string {
+	args := []string{}
+	for i, cert := range d.caCerts {
+		name := strings.TrimSuffix(filepath.Base(cert), filepath.Ext(cert))
+		containerPath := fmt.Sprintf("%s/%d-%s.crt", caCertsDir, i, name)
+		args = append(args, "-v", d.volume(cert, containerPath, "ro"))
+	}
+	return args
+}
@@ -0,0 +1,38 @@
+package build
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuilder_caCertsArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		caCerts []string
+		want    []string
+	}{
+		{
+			name:    "no certificates",
+			caCerts: nil,
+			want:    []string{},
+		},
+		{
+			name:    "certificates",
+			caCerts: []string{"/etc/pki/corp-root.pem", "/home/fyne/registry.crt"},
+			want: []string{
+				"-v", "/etc/pki/corp-root.pem:/usr/local/share/ca-certificates/fyne-cross/0-corp-root.crt:ro",
+				"-v", "/home/fyne/registry.crt:/usr/local/share/ca-certificates/fyne-cross/1-registry.crt:ro",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Builder{
+				caCerts: tt.caCerts,
+			}
+			if got := d.caCertsArgs(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Builder.caCertsArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package build
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// cacheComponents represents the components of the fyne-cross cache directory
+// with the relative folders
+var cacheComponents = map[string]string{
+	"modules":    "pkg/mod",
+	"gocache":    "gocache",
+	"toolchains": "toolchains",
+	"ccache":     "ccache",
+}
+
+// CacheComponents returns the sorted list of the components of the cache directory
+func CacheComponents() []string {
+	names := []string{}
+	for name := range cacheComponents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CacheUsage represents the disk usage of a cache component
+type CacheUsage struct {
+	// Component is the name of the component, see CacheComponents
+	Component string
+	// Dir is the folder of the component
+	Dir string
+	// Size is the size in bytes
+	Size int64
+}
+
+// PruneOptions represents the options to prune the cache directory
+type PruneOptions struct {
+	// Components are the components to prune. Default to all the components
+	Components []string
+	// OlderThan removes the entries not used since the specified duration
+	OlderThan time.Duration
+	// MaxSize removes the least recently used entries until the size of the pruned components
+	// is under the specified bytes
+	MaxSize int64
+}
+
+// cacheEntry represents a unit of the cache removed by the prune.
+// The entries are the files of the build caches, each toolchain and the whole module cache,
+// since removing the single modules could leave the module cache inconsistent
+type cacheEntry struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// fyneCrossCacheDir returns the fyne-cross folder into the cache directory.
+// Default to the user cache directory
+func fyneCrossCacheDir(cacheDir string) (string, error) {
+	if cacheDir == "" {
+		var err error
+		cacheDir, err = os.UserCacheDir()
+		if err != nil {
+			return "", fmt.Errorf("Cannot get the path for cache directory %s", err)
+		}
+	}
+	return filepath.Join(cacheDir, "fyne-cross"), nil
+}
+
+// CacheSize returns the disk usage of each component of the cache directory.
+// Default to the user cache directory
+func CacheSize(cacheDir string) ([]CacheUsage, error) {
+	root, err := fyneCrossCacheDir(cacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := []CacheUsage{}
+	for _, name := range CacheComponents() {
+		dir := filepath.Join(root, filepath.FromSlash(cacheComponents[name]))
+		entries, err := cacheEntries(name, dir)
+		if err != nil {
+			return nil, err
+		}
+		u := CacheUsage{Component: name, Dir: dir}
+		for _, e := range entries {
+			u.Size += e.size
+		}
+		usage = append(usage, u)
+	}
+	return usage, nil
+}
+
+// PruneCache removes the entries of the cache directory according to opts and returns the freed bytes.
+// All the entries of the components are removed when neither an age nor a size limit is set.
+// The prune waits for the running builds to complete
+func PruneCache(cacheDir string, opts PruneOptions) (int64, error) {
+	root, err := fyneCrossCacheDir(cacheDir)
+	if err != nil {
+		return 0, err
+	}
+
+	names, err := components(opts.Components)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = os.Stat(root)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+
+	sessionLock, err := lockFile(filepath.Join(root, sessionLockFileName))
+	if err != nil {
+		return 0, fmt.Errorf("Cannot lock the cache directory %s", err)
+	}
+	defer sessionLock.unlock()
+
+	entries := []cacheEntry{}
+	for _, name := range names {
+		e, err := cacheEntries(name, filepath.Join(root, filepath.FromSlash(cacheComponents[name])))
+		if err != nil {
+			return 0, err
+		}
+		entries = append(entries, e...)
+	}
+
+	var freed int64
+	for _, e := range pruneEntries(entries, opts, time.Now()) {
+		err = removeCacheEntry(e.path)
+		if err != nil {
+			return freed, fmt.Errorf("Cannot remove %s %s", e.path, err)
+		}
+		freed += e.size
+	}
+	return freed, nil
+}
+
+// pruneEntries returns the entries to remove according to opts, the least recently used first
+func pruneEntries(entries []cacheEntry, opts PruneOptions, now time.Time) []cacheEntry {
+	sorted := append([]cacheEntry{}, entries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].modTime.Before(sorted[j].modTime)
+	})
+	if opts.OlderThan == 0 && opts.MaxSize == 0 {
+		return sorted
+	}
+
+	var size int64
+	for _, e := range sorted {
+		size += e.size
+	}
+
+	pruned := []cacheEntry{}
+	for _, e := range sorted {
+		old := opts.OlderThan > 0 && now.Sub(e.modTime) > opts.OlderThan
+		oversize := opts.MaxSize > 0 && size > opts.MaxSize
+		if !old && !oversize {
+			break
+		}
+		pruned = append(pruned, e)
+		size -= e.size
+	}
+	return pruned
+}
+
+// cacheEntries returns the entries of the cache component located into dir, see cacheEntry
+func cacheEntries(name string, dir string) ([]cacheEntry, error) {
+	_, err := os.Stat(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	switch name {
+	case "modules":
+		e, err := dirEntry(dir)
+		if err != nil || e.size == 0 {
+			return nil, err
+		}
+		return []cacheEntry{e}, nil
+	case "toolchains":
+		files, err := filepath.Glob(filepath.Join(dir, "go*"))
+		if err != nil {
+			return nil, err
+		}
+		entries := []cacheEntry{}
+		for _, f := range files {
+			e, err := dirEntry(f)
+			if err != nil {
+				return nil, err
+			}
+			// the toolchain folder is touched on each use, see toolchainScript
+			info, err := os.Stat(f)
+			if err != nil {
+				return nil, err
+			}
+			e.modTime = info.ModTime()
+			entries = append(entries, e)
+		}
+		return entries, nil
+	}
+
+	entries := []cacheEntry{}
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			entries = append(entries, cacheEntry{path: path, size: info.Size(), modTime: info.ModTime()})
+		}
+		return nil
+	})
+	return entries, err
+}
+
+// dirEntry returns the entry of the whole dir. The modification time is the one of the newest file
+func dirEntry(dir string) (cacheEntry, error) {
+	e := cacheEntry{path: dir}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		if info.ModTime().After(e.modTime) {
+			e.modTime = info.ModTime()
+		}
+		e.size += info.Size()
+		return nil
+	})
+	return e, err
+}
+
+// removeCacheEntry removes the entry at path. The write permission is restored on the folders
+// since the module cache and the toolchains are read-only
+func removeCacheEntry(path string) error {
+	filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err == nil && info.IsDir() {
+			os.Chmod(p, info.Mode().Perm()|0700)
+		}
+		return nil
+	})
+	return os.RemoveAll(path)
+}
@@ -0,0 +1,255 @@
+package build
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// components returns the components, all if none is specified, checking they are known
+func components(names []string) ([]string, error) {
+	if len(names) == 0 {
+		return CacheComponents(), nil
+	}
+	for _, name := range names {
+		if _, ok := cacheComponents[name]; !ok {
+			return nil, fmt.Errorf("Unknown cache component %q", name)
+		}
+	}
+	return names, nil
+}
+
+// ExportCache writes the components of the cache directory, all if none is specified, to w
+// as a gzipped tarball, i.e. to persist the cache of an ephemeral CI runner as a pipeline artifact.
+// The export waits for the running downloads to complete and blocks the cache prune
+func ExportCache(cacheDir string, w io.Writer, names []string) error {
+	root, err := fyneCrossCacheDir(cacheDir)
+	if err != nil {
+		return err
+	}
+	names, err = components(names)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(root, 0755)
+	if err != nil {
+		return err
+	}
+
+	sessionLock, err := lockFileShared(filepath.Join(root, sessionLockFileName))
+	if err != nil {
+		return fmt.Errorf("Cannot lock the cache directory %s", err)
+	}
+	defer sessionLock.unlock()
+	lock, err := lockFile(filepath.Join(root, lockFileName))
+	if err != nil {
+		return fmt.Errorf("Cannot lock the cache directory %s", err)
+	}
+	defer lock.unlock()
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	for _, name := range names {
+		dir := filepath.Join(root, filepath.FromSlash(cacheComponents[name]))
+		err = exportDir(tw, root, dir)
+		if err != nil {
+			return fmt.Errorf("Cannot export the %s cache %s", name, err)
+		}
+	}
+	err = tw.Close()
+	if err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// exportDir writes the folders and the regular files of dir to tw. The names are relative to root
+func exportDir(tw *tar.Writer, root string, dir string) error {
+	_, err := os.Stat(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		err = tw.WriteHeader(hdr)
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+}
+
+// ImportCache restores into the cache directory the components of the gzipped tarball
+// written by ExportCache. The existing files are kept, since the cache entries are immutable.
+// The import waits for the running builds to complete
+func ImportCache(cacheDir string, r io.Reader) error {
+	root, err := fyneCrossCacheDir(cacheDir)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(root, 0755)
+	if err != nil {
+		return err
+	}
+
+	sessionLock, err := lockFile(filepath.Join(root, sessionLockFileName))
+	if err != nil {
+		return fmt.Errorf("Cannot lock the cache directory %s", err)
+	}
+	defer sessionLock.unlock()
+
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("Cannot read the cache archive %s", err)
+	}
+	defer gr.Close()
+
+	// the folders are created writable and get their mode and modification time once all
+	// the files are extracted, since the module cache and the toolchains are read-only
+	dirs := map[string]archiveDir{}
+	defer func() {
+		for dir, a := range dirs {
+			os.Chmod(dir, a.mode)
+			if !a.modTime.IsZero() {
+				os.Chtimes(dir, a.modTime, a.modTime)
+			}
+		}
+	}()
+
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Cannot read the cache archive %s", err)
+		}
+
+		dest, err := cacheArchivePath(root, hdr.Name)
+		if err != nil {
+			return err
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = importDir(dest, archiveDir{mode: hdr.FileInfo().Mode().Perm(), modTime: hdr.ModTime}, dirs)
+		case tar.TypeReg:
+			err = importFile(dest, hdr, tr, dirs)
+		}
+		if err != nil {
+			return fmt.Errorf("Cannot import %s %s", hdr.Name, err)
+		}
+	}
+}
+
+// cacheArchivePath returns the destination of the archive entry name into root.
+// Only the entries of the cache components are accepted
+func cacheArchivePath(root string, name string) (string, error) {
+	clean := path.Clean(strings.TrimSuffix(name, "/"))
+	for _, dir := range cacheComponents {
+		if clean == dir || strings.HasPrefix(clean, dir+"/") {
+			return filepath.Join(root, filepath.FromSlash(clean)), nil
+		}
+	}
+	return "", fmt.Errorf("Invalid cache archive entry %q", name)
+}
+
+// archiveDir represents the mode and the modification time a folder gets once the import completes
+type archiveDir struct {
+	mode    os.FileMode
+	modTime time.Time
+}
+
+// importDir creates the folder dest, writable until the import completes, see ImportCache
+func importDir(dest string, a archiveDir, dirs map[string]archiveDir) error {
+	info, err := os.Stat(dest)
+	switch {
+	case os.IsNotExist(err):
+		err = os.MkdirAll(dest, 0755)
+		if err != nil {
+			return err
+		}
+		dirs[dest] = a
+		return nil
+	case err != nil:
+		return err
+	}
+
+	// update the folders created by the import, i.e. the parent folders created before their entry
+	if _, ok := dirs[dest]; ok {
+		if !a.modTime.IsZero() {
+			dirs[dest] = a
+		}
+		return nil
+	}
+
+	// make the existing read-only folders writable until the import completes
+	if info.Mode().Perm()&0200 == 0 {
+		dirs[dest] = archiveDir{mode: info.Mode().Perm(), modTime: info.ModTime()}
+		return os.Chmod(dest, info.Mode().Perm()|0700)
+	}
+	return nil
+}
+
+// importFile writes the content of the regular file entry to dest, unless it exists
+func importFile(dest string, hdr *tar.Header, r io.Reader, dirs map[string]archiveDir) error {
+	_, err := os.Stat(dest)
+	if err == nil {
+		return nil
+	}
+
+	err = importDir(filepath.Dir(dest), archiveDir{mode: 0755}, dirs)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, hdr.FileInfo().Mode().Perm())
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	if err != nil {
+		f.Close()
+		os.Remove(dest)
+		return err
+	}
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+	// keep the modification time, used to track the last use for the cache prune
+	return os.Chtimes(dest, hdr.ModTime, hdr.ModTime)
+}
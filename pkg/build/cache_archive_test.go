@@ -0,0 +1,111 @@
+package build
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestExportCache_ImportCache(t *testing.T) {
+	src, err := ioutil.TempDir("", "fyne-cross")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+
+	mtime := time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)
+	files := map[string]string{
+		"pkg/mod/fyne.io/fyne@v1.4.3/app.go": "package app",
+		"gocache/00/0011-d":                  "build output",
+		"ccache/a/b/c.o":                     "object",
+		"hashes/0123.json":                   "{}",
+	}
+	for name, content := range files {
+		path := filepath.Join(src, "fyne-cross", filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		err = ioutil.WriteFile(path, []byte(content), 0444)
+		if err != nil {
+			t.Fatal(err)
+		}
+		os.Chtimes(path, mtime, mtime)
+	}
+	// the module cache folders are read-only
+	os.Chmod(filepath.Join(src, "fyne-cross", "pkg", "mod", "fyne.io", "fyne@v1.4.3"), 0555)
+	defer os.Chmod(filepath.Join(src, "fyne-cross", "pkg", "mod", "fyne.io", "fyne@v1.4.3"), 0755)
+
+	var buf bytes.Buffer
+	err = ExportCache(src, &buf, []string{"modules", "gocache"})
+	if err != nil {
+		t.Fatalf("ExportCache() error = %v", err)
+	}
+
+	dest, err := ioutil.TempDir("", "fyne-cross")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeCacheEntry(dest)
+
+	err = ImportCache(dest, bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ImportCache() error = %v", err)
+	}
+	// the existing files are kept
+	err = ImportCache(dest, bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ImportCache() on existing cache error = %v", err)
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dest, "fyne-cross", filepath.FromSlash(name))
+		b, err := ioutil.ReadFile(path)
+		exported := name != "ccache/a/b/c.o" && name != "hashes/0123.json"
+		if !exported {
+			if err == nil {
+				t.Errorf("ImportCache() unexpected file %s", name)
+			}
+			continue
+		}
+		if err != nil || string(b) != content {
+			t.Errorf("ImportCache() %s = %q, %v, want %q", name, b, err, content)
+			continue
+		}
+		info, _ := os.Stat(path)
+		if !info.ModTime().Equal(mtime) {
+			t.Errorf("ImportCache() %s modification time = %v, want %v", name, info.ModTime(), mtime)
+		}
+	}
+
+	info, err := os.Stat(filepath.Join(dest, "fyne-cross", "pkg", "mod", "fyne.io", "fyne@v1.4.3"))
+	if err != nil || info.Mode().Perm() != 0555 {
+		t.Errorf("ImportCache() module folder mode = %v, %v, want %v", info.Mode().Perm(), err, os.FileMode(0555))
+	}
+}
+
+func Test_cacheArchivePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		entry   string
+		want    string
+		wantErr bool
+	}{
+		{name: "module", entry: "pkg/mod/fyne.io/fyne@v1.4.3/app.go", want: "/cache/pkg/mod/fyne.io/fyne@v1.4.3/app.go"},
+		{name: "component folder", entry: "gocache/", want: "/cache/gocache"},
+		{name: "unknown folder", entry: "hashes/0123.json", wantErr: true},
+		{name: "path traversal", entry: "gocache/../../etc/passwd", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cacheArchivePath("/cache", tt.entry)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("cacheArchivePath() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != filepath.FromSlash(tt.want) {
+				t.Errorf("cacheArchivePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package build
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_pruneEntries(t *testing.T) {
+	now := time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)
+	entries := []cacheEntry{
+		{path: "recent", size: 10, modTime: now.Add(-time.Hour)},
+		{path: "old", size: 20, modTime: now.Add(-60 * 24 * time.Hour)},
+		{path: "week", size: 30, modTime: now.Add(-7 * 24 * time.Hour)},
+	}
+
+	tests := []struct {
+		name string
+		opts PruneOptions
+		want []string
+	}{
+		{name: "all", opts: PruneOptions{}, want: []string{"old", "week", "recent"}},
+		{name: "older than", opts: PruneOptions{OlderThan: 30 * 24 * time.Hour}, want: []string{"old"}},
+		{name: "max size", opts: PruneOptions{MaxSize: 30}, want: []string{"old", "week"}},
+		{name: "max size not exceeded", opts: PruneOptions{MaxSize: 100}, want: []string{}},
+		{name: "older than and max size", opts: PruneOptions{OlderThan: 24 * time.Hour, MaxSize: 100}, want: []string{"old", "week"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, e := range pruneEntries(entries, tt.opts, now) {
+				got = append(got, e.path)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pruneEntries() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPruneCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fyne-cross")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	write := func(name string, size int, age time.Duration) {
+		path := filepath.Join(dir, "fyne-cross", filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		err := ioutil.WriteFile(path, make([]byte, size), 0444)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mtime := time.Now().Add(-age)
+		os.Chtimes(path, mtime, mtime)
+	}
+	write("pkg/mod/fyne.io/fyne@v1.4.3/app.go", 100, 0)
+	write("gocache/linux-amd64/00/old-d", 10, 60*24*time.Hour)
+	write("gocache/linux-amd64/01/new-d", 20, time.Hour)
+	write("toolchains/go1.16.3/bin/go", 40, 60*24*time.Hour)
+	old := time.Now().Add(-60 * 24 * time.Hour)
+	os.Chtimes(filepath.Join(dir, "fyne-cross", "toolchains", "go1.16.3"), old, old)
+	// the module cache is read-only
+	os.Chmod(filepath.Join(dir, "fyne-cross", "pkg", "mod", "fyne.io", "fyne@v1.4.3"), 0555)
+
+	usage, err := CacheSize(dir)
+	if err != nil {
+		t.Fatalf("CacheSize() error = %v", err)
+	}
+	gotSizes := map[string]int64{}
+	for _, u := range usage {
+		gotSizes[u.Component] = u.Size
+	}
+	wantSizes := map[string]int64{"ccache": 0, "gocache": 30, "modules": 100, "toolchains": 40}
+	if !reflect.DeepEqual(gotSizes, wantSizes) {
+		t.Errorf("CacheSize() = %v, want %v", gotSizes, wantSizes)
+	}
+
+	freed, err := PruneCache(dir, PruneOptions{OlderThan: 30 * 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("PruneCache() error = %v", err)
+	}
+	if freed != 50 {
+		t.Errorf("PruneCache() freed = %d, want 50", freed)
+	}
+
+	freed, err = PruneCache(dir, PruneOptions{Components: []string{"modules"}})
+	if err != nil {
+		t.Fatalf("PruneCache() error = %v", err)
+	}
+	if freed != 100 {
+		t.Errorf("PruneCache() freed = %d, want 100", freed)
+	}
+
+	_, err = PruneCache(dir, PruneOptions{Components: []string{"unknown"}})
+	if err == nil {
+		t.Errorf("PruneCache() error = nil, want error for an unknown component")
+	}
+}
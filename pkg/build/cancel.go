@@ -0,0 +1,85 @@
+package build
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// sessionLabel is the label of the containers run by a builder, used to remove them
+// on cancellation, since the "docker run" client being killed leaves the container running
+const sessionLabel = "fyne-cross.session"
+
+// newSessionID returns a random identifier of the containers run by a builder
+func newSessionID() string {
+	b := make([]byte, 8)
+	_, err := rand.Read(b)
+	if err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// canceled returns true if the running operation has been canceled
+func (d *Builder) canceled() bool {
+	return d.ctx != nil && d.ctx.Err() == context.Canceled
+}
+
+// timeoutGrace is the delay after the timeout before the docker client is killed, so that the
+// commands run into the container session are killed first by the timeout command, see execArgs
+var timeoutGrace = 5 * time.Second
+
+// withTimeout runs fn limited by the timeout option, if any. The commands run into the container
+// session are killed on timeout into the container, the context of the running operation
+// expiring only after timeoutGrace. The dedicated containers still running on timeout are removed,
+// see removeContainers
+func (d *Builder) withTimeout(fn func() error) error {
+	if d.timeout == 0 {
+		return fn()
+	}
+
+	orig := d.ctx
+	parent := orig
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(parent, d.timeout+timeoutGrace)
+	defer cancel()
+
+	start := time.Now()
+	d.ctx = ctx
+	err := fn()
+	d.ctx = orig
+	if err != nil && (ctx.Err() == context.DeadlineExceeded || time.Since(start) >= d.timeout) {
+		d.removeContainers()
+		return fmt.Errorf("Timeout after %s %s", d.timeout, err)
+	}
+	return err
+}
+
+// removeContainers removes the dedicated containers run by the builder, i.e. to download the
+// dependencies, if any. The container session is removed by stop.
+// The context is not used so that the containers are removed also on cancellation
+func (d *Builder) removeContainers() error {
+	if d.session == "" {
+		return nil
+	}
+	out, err := exec.Command("docker", "ps", "-aq", "--filter", "label="+sessionLabel+"="+d.session).Output()
+	if err != nil {
+		return err
+	}
+	ids := []string{}
+	for _, id := range strings.Fields(string(out)) {
+		if d.containerID == "" || !strings.HasPrefix(d.containerID, id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return exec.Command("docker", append([]string{"rm", "-f"}, ids...)...).Run()
+}
@@ -0,0 +1,88 @@
+package build
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuilder_withTimeout(t *testing.T) {
+	d := &Builder{timeout: 10 * time.Millisecond}
+	// the command killed into the container by the timeout command
+	err := d.withTimeout(func() error {
+		deadline, ok := d.ctx.Deadline()
+		if !ok || time.Until(deadline) < timeoutGrace {
+			return errors.New("the docker client must be killed after the grace delay")
+		}
+		time.Sleep(20 * time.Millisecond)
+		return errors.New("exit status 137")
+	})
+	if err == nil || !strings.HasPrefix(err.Error(), "Timeout after 10ms exit status 137") {
+		t.Errorf("Builder.withTimeout() error = %v, want timeout", err)
+	}
+	if d.ctx != nil {
+		t.Errorf("Builder.withTimeout() context not restored")
+	}
+
+	want := errors.New("build failed")
+	err = d.withTimeout(func() error { return want })
+	if err != want {
+		t.Errorf("Builder.withTimeout() error = %v, want %v", err, want)
+	}
+
+	d = &Builder{}
+	err = d.withTimeout(func() error {
+		if d.ctx != nil {
+			return errors.New("unexpected context")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Builder.withTimeout() without timeout error = %v", err)
+	}
+}
+
+func TestBuilder_execArgs_timeout(t *testing.T) {
+	d := &Builder{
+		containerID: "fyne-cross",
+		timeout:     90 * time.Second,
+	}
+	want := []string{"exec", "fyne-cross", "timeout", "-s", "KILL", "90s", "go", "build"}
+	if got := d.execArgs(nil, []string{"go", "build"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Builder.execArgs() = %v, want %v", got, want)
+	}
+}
+
+func TestBuilder_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &Builder{ctx: ctx}
+	if d.canceled() {
+		t.Errorf("Builder.canceled() = true before cancel")
+	}
+	cancel()
+	if !d.canceled() {
+		t.Errorf("Builder.canceled() = false after cancel")
+	}
+}
+
+func TestBuilder_defaultArgs_session(t *testing.T) {
+	d := &Builder{
+		workDir:  "/home/fyne",
+		cacheDir: "/tmp/cache",
+		rootless: true,
+		session:  "0123456789abcdef",
+	}
+	want := []string{
+		"run", "--rm",
+		"-w", "/app",
+		"-v", "/home/fyne:/app",
+		"-v", "/tmp/cache/fyne-cross:/go",
+		"--label", "fyne-cross.session=0123456789abcdef",
+	}
+	if got := d.defaultArgs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Builder.defaultArgs() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,200 @@
+package build
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// deltaDir is the folder of the binary diffs into the build folder
+const deltaDir = "delta"
+
+// deltaFromDir is the container folder where the previous release folder is mounted read-only
+const deltaFromDir = "/fyne-cross/delta-from"
+
+// updateManifestFile is the name of the update manifest written into the output folder
+const updateManifestFile = "update-manifest.json"
+
+// updateManifest describes the artifacts of a release along with the binary diffs
+// from the previous release, used by the self-updating applications
+type updateManifest struct {
+	Version         string        `json:"version"`
+	PreviousVersion string        `json:"previous_version"`
+	Artifacts       []updateEntry `json:"artifacts"`
+}
+
+// updateEntry describes an executable of the release and the binary diff
+// from the previous release executable of the same target, if changed
+type updateEntry struct {
+	Artifact
+	Patch *updatePatch `json:"patch,omitempty"`
+}
+
+// updatePatch describes a binary diff in the bsdiff format
+type updatePatch struct {
+	// FromSHA256 is the checksum of the previous release executable the patch applies to
+	FromSHA256 string `json:"from_sha256"`
+	// File is the patch path relative to the build folder
+	File   string `json:"file"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// readManifestFile returns the build manifest at path
+func readManifestFile(path string) (manifest, error) {
+	m := manifest{}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return m, err
+	}
+	err = json.Unmarshal(b, &m)
+	return m, err
+}
+
+// resolveDeltaFrom validates the previous release folder and returns its absolute path.
+// The folder must contain the build manifest of the previous release
+func resolveDeltaFrom(dir string) (string, error) {
+	if dir == "" {
+		return "", nil
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	_, err = readManifestFile(filepath.Join(abs, manifestFile))
+	if err != nil {
+		return "", fmt.Errorf("Cannot read the build manifest of the previous release %s", err)
+	}
+	return abs, nil
+}
+
+// deltaArgs returns the arguments used to mount the previous release folder into the container, if any
+func (d *Builder) deltaArgs() []string {
+	if d.deltaFrom == "" {
+		return nil
+	}
+	return []string{"-v", d.volume(d.deltaFrom, deltaFromDir, "ro")}
+}
+
+// previousArtifact returns the executable of the previous release built for the same target
+// and package of a, if any. The previous executable is checked against its checksum
+func previousArtifact(dir string, previous []Artifact, a Artifact) (Artifact, bool, error) {
+	for _, p := range previous {
+		if p.Target != a.Target || p.Package != a.Package || p.Package == "" {
+			continue
+		}
+		actual, err := newArtifact(p.Target, filepath.Join(dir, filepath.FromSlash(p.File)))
+		if os.IsNotExist(err) {
+			return p, false, nil
+		}
+		if err != nil {
+			return p, false, err
+		}
+		if actual.SHA256 != p.SHA256 {
+			return p, false, fmt.Errorf("The checksum of the previous release artifact %s does not match its build manifest", p.File)
+		}
+		return p, true, nil
+	}
+	return Artifact{}, false, nil
+}
+
+// patchFile returns the path of the binary diff of the artifact relative to the build folder,
+// i.e. delta/myapp-linux-amd64-1.0.0-1.1.0.bsdiff
+func patchFile(a Artifact, from string, to string) string {
+	name := assetName(a.File)
+	if ext := path.Ext(name); ext == ".exe" {
+		name = name[:len(name)-len(ext)]
+	}
+	return path.Join(deltaDir, fmt.Sprintf("%s-%s-%s.bsdiff", name, from, to))
+}
+
+// generateDeltas generates via bsdiff the binary diffs between the executables of the previous
+// release and the built ones, and writes the update manifest. The executables unchanged or
+// missing from the previous release have no patch. It returns the patches and the update manifest
+// as artifacts
+func (d *Builder) generateDeltas(artifacts []Artifact) ([]Artifact, error) {
+	prev, err := readManifestFile(filepath.Join(d.deltaFrom, manifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("Cannot read the build manifest of the previous release %s", err)
+	}
+	from, to := prev.Version, d.version
+	if from == "" || to == "" || from == to {
+		return nil, fmt.Errorf("Different versions are required for the previous release and the build, see the version option")
+	}
+
+	um := updateManifest{
+		Version:         to,
+		PreviousVersion: from,
+		Artifacts:       []updateEntry{},
+	}
+	patches := []Artifact{}
+	for _, a := range artifacts {
+		if a.Package == "" {
+			continue
+		}
+		entry := updateEntry{Artifact: a}
+
+		p, ok, err := previousArtifact(d.deltaFrom, prev.Artifacts, a)
+		if err != nil {
+			return nil, err
+		}
+		if ok && p.SHA256 != a.SHA256 {
+			file := patchFile(a, from, to)
+			err = os.MkdirAll(filepath.Join(d.workDir, "build", deltaDir), 0755)
+			if err != nil {
+				return nil, err
+			}
+			_, err = d.step(fmt.Sprintf("Generating the delta %s", file), func() error {
+				return d.exec(d.bsdiffArgs(p.File, a.File, file))
+			})
+			if err != nil {
+				return nil, fmt.Errorf("Cannot generate the delta for %s %s", a.File, err)
+			}
+
+			pa, err := newArtifact(a.Target, filepath.Join(d.workDir, "build", filepath.FromSlash(file)))
+			if err != nil {
+				return nil, err
+			}
+			pa.File = file
+			patches = append(patches, pa)
+			entry.Patch = &updatePatch{
+				FromSHA256: p.SHA256,
+				File:       file,
+				Size:       pa.Size,
+				SHA256:     pa.SHA256,
+			}
+		}
+		um.Artifacts = append(um.Artifacts, entry)
+	}
+
+	b, err := json.MarshalIndent(um, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	umPath := filepath.Join(d.workDir, "build", updateManifestFile)
+	err = ioutil.WriteFile(umPath, b, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot write the update manifest %s", err)
+	}
+	fmt.Fprintf(d.stdout, "Update manifest: %s\n", umPath)
+
+	a, err := newArtifact("", umPath)
+	if err != nil {
+		return nil, err
+	}
+	return append(patches, a), nil
+}
+
+// bsdiffArgs returns the arguments for the "bsdiff" command generating the patch from the previous
+// release file to the built file. The paths are relative to the respective folders
+func (d *Builder) bsdiffArgs(previous string, current string, patch string) []string {
+	return d.execArgs(nil, []string{
+		"bsdiff",
+		deltaFromDir + "/" + previous,
+		d.appDir() + "/build/" + current,
+		d.appDir() + "/build/" + patch,
+	})
+}
@@ -0,0 +1,138 @@
+package build
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_patchFile(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want string
+	}{
+		{
+			name: "linux",
+			file: "myapp-linux-amd64",
+			want: "delta/myapp-linux-amd64-1.0.0-1.1.0.bsdiff",
+		},
+		{
+			name: "windows with target subdir",
+			file: "windows-amd64/myapp-windows-amd64.exe",
+			want: "delta/myapp-windows-amd64-1.0.0-1.1.0.bsdiff",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := patchFile(Artifact{File: tt.file}, "1.0.0", "1.1.0"); got != tt.want {
+				t.Errorf("patchFile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_previousArtifact(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fyne-cross-delta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "myapp-linux-amd64"), []byte("fyne"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev, err := newArtifact("linux/amd64", filepath.Join(dir, "myapp-linux-amd64"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev.Package = "."
+	corrupted := Artifact{Target: "windows/amd64", Package: ".", File: "myapp-linux-amd64", SHA256: "00"}
+	missing := Artifact{Target: "darwin/amd64", Package: ".", File: "myapp-darwin-amd64", SHA256: "00"}
+	previous := []Artifact{prev, corrupted, missing}
+
+	tests := []struct {
+		name    string
+		a       Artifact
+		want    Artifact
+		wantOK  bool
+		wantErr bool
+	}{
+		{
+			name:   "found",
+			a:      Artifact{Target: "linux/amd64", Package: "."},
+			want:   prev,
+			wantOK: true,
+		},
+		{
+			name: "different package",
+			a:    Artifact{Target: "linux/amd64", Package: "./cmd/other"},
+			want: Artifact{},
+		},
+		{
+			name: "missing file",
+			a:    Artifact{Target: "darwin/amd64", Package: "."},
+			want: missing,
+		},
+		{
+			name:    "checksum mismatch",
+			a:       Artifact{Target: "windows/amd64", Package: "."},
+			want:    corrupted,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := previousArtifact(dir, previous, tt.a)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("previousArtifact() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("previousArtifact() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestBuilder_bsdiffArgs(t *testing.T) {
+	d := &Builder{
+		containerID: "fyne-cross",
+		gomod:       true,
+	}
+	want := []string{
+		"exec", "fyne-cross",
+		"bsdiff",
+		"/fyne-cross/delta-from/myapp-linux-amd64",
+		"/app/build/myapp-linux-amd64",
+		"/app/build/delta/myapp-linux-amd64-1.0.0-1.1.0.bsdiff",
+	}
+	got := d.bsdiffArgs("myapp-linux-amd64", "myapp-linux-amd64", "delta/myapp-linux-amd64-1.0.0-1.1.0.bsdiff")
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Builder.bsdiffArgs() = %v, want %v", got, want)
+	}
+}
+
+func TestBuilder_sessionArgs_deltaFrom(t *testing.T) {
+	d := &Builder{
+		workDir:   "/home/fyne",
+		cacheDir:  "/tmp/cache",
+		rootless:  true,
+		deltaFrom: "/home/fyne/release-1.0.0",
+	}
+	want := []string{
+		"run", "--rm",
+		"-w", "/app",
+		"-v", "/home/fyne:/app",
+		"-v", "/tmp/cache/fyne-cross:/go",
+		"-d",
+		"-v", "/home/fyne/release-1.0.0:/fyne-cross/delta-from:ro",
+		"-v", "/tmp/cache/fyne-cross/pkg/mod:/go/pkg/mod:ro",
+	}
+	if got := d.sessionArgs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Builder.sessionArgs() = %v, want %v", got, want)
+	}
+}
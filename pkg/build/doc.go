@@ -0,0 +1,20 @@
+/*
+Package build cross compiles Fyne applications (https://fyne.io) into the fyne-cross docker image.
+
+It is the library behind the fyne-cross command and can be used to drive the
+cross builds programmatically, i.e. from custom release scripts:
+
+	artifacts, err := build.Build(context.Background(), build.Options{
+		Targets:  []string{"linux/amd64", "windows/amd64"},
+		Packages: []string{"./cmd/myapp"},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, a := range artifacts {
+		fmt.Println(a.Target, a.File, a.SHA256)
+	}
+
+Docker must be available in PATH.
+*/
+package build
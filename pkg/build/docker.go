@@ -0,0 +1,410 @@
+package build
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultImage is the fyne-cross docker image
+const DefaultImage = "lucor/fyne-cross"
+
+// readyFile is the file created by the docker entrypoint once the container
+// is ready to run commands
+const readyFile = "/tmp/fyne-cross.ready"
+
+// docker returns the command to run docker with the specified arguments.
+// The command is killed when the context of the running operation is done
+func (d *Builder) docker(args ...string) *exec.Cmd {
+	ctx := d.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return exec.CommandContext(ctx, "docker", args...)
+}
+
+// imageName returns the docker image used to run the containers
+func (d *Builder) imageName() string {
+	if d.image == "" {
+		return DefaultImage
+	}
+	return d.image
+}
+
+// checkRequirements checks if all the build requirements are satisfied
+func (d *Builder) checkRequirements() error {
+	err := d.docker("version").Run()
+	if err != nil {
+		return fmt.Errorf("Missed requirement: docker binary not found in PATH")
+	}
+	d.rootless = d.isRootlessEngine()
+
+	// commands run as the current user to handle the mount permissions, unless a user is specified.
+	// Not needed on rootless engines since the container root is the host user
+	if d.uid == "" && !d.rootless {
+		d.uid, d.gid = currentUser()
+	}
+	return nil
+}
+
+// isRootlessEngine returns true if the container engine is running in rootless mode
+func (d *Builder) isRootlessEngine() bool {
+	// podman, also when aliased as docker
+	out, err := d.docker("info", "--format", "{{.Host.Security.Rootless}}").Output()
+	if err == nil {
+		return strings.TrimSpace(string(out)) == "true"
+	}
+	// docker
+	out, err = d.docker("info", "--format", "{{.SecurityOptions}}").Output()
+	return err == nil && strings.Contains(string(out), "rootless")
+}
+
+// start starts the container session used to run the build commands.
+// The container is kept running until stop is called. A shared lock is held on the cache
+// directory for the whole session, so that the cache is not pruned while in use
+func (d *Builder) start() error {
+	// create the module cache folder to avoid it will be owned by the container root user
+	err := os.MkdirAll(filepath.Join(d.cacheDir, "fyne-cross", "pkg", "mod"), 0755)
+	if err != nil {
+		return err
+	}
+
+	d.sessionLock, err = lockFileShared(filepath.Join(d.cacheDir, "fyne-cross", sessionLockFileName))
+	if err != nil {
+		return fmt.Errorf("Cannot lock the cache directory %s", err)
+	}
+
+	args := append(d.sessionArgs(), d.imageName(), "sleep", "infinity")
+	if d.verbose {
+		fmt.Fprintf(d.stdout, "docker %s\n", strings.Join(args, " "))
+	}
+	cmd := d.docker(args...)
+	cmd.Stderr = d.stderr
+	out, err := cmd.Output()
+	if err != nil {
+		d.stop()
+		return err
+	}
+	d.containerID = strings.TrimSpace(string(out))
+
+	// wait for the entrypoint to complete the container setup
+	for i := 0; i < 60; i++ {
+		err = d.docker("exec", d.containerID, "test", "-f", readyFile).Run()
+		if err == nil {
+			return nil
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	d.stop()
+	return fmt.Errorf("Timeout waiting for the container %s to be ready", d.containerID)
+}
+
+// stop stops and removes the container session.
+// The context is not used so that the container is removed also on cancellation
+func (d *Builder) stop() error {
+	if d.sessionLock != nil {
+		d.sessionLock.unlock()
+		d.sessionLock = nil
+	}
+	if d.containerID == "" {
+		return nil
+	}
+	err := exec.Command("docker", "rm", "-f", d.containerID).Run()
+	d.containerID = ""
+	if d.canceled() {
+		d.removeContainers()
+	}
+	return err
+}
+
+// exec runs docker with the specified arguments attached to stdin, stdout and stderr
+func (d *Builder) exec(args []string) error {
+	return d.execOutput(args, d.stdout, d.stderr)
+}
+
+// execOutput runs the docker command with the specified args writing its output to stdout and stderr
+func (d *Builder) execOutput(args []string, stdout io.Writer, stderr io.Writer) error {
+	if d.verbose {
+		fmt.Fprintf(stdout, "docker %s\n", strings.Join(args, " "))
+	}
+	cmd := d.docker(args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
+// goGet downloads the application dependencies, see goGetArgs.
+// Dependencies are downloaded into a dedicated container holding an exclusive lock
+// on the cache directory, so that concurrent fyne-cross processes do not race
+func (d *Builder) goGet() error {
+	dir := filepath.Join(d.cacheDir, "fyne-cross")
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	lock, err := lockFile(filepath.Join(dir, lockFileName))
+	if err != nil {
+		return fmt.Errorf("Cannot lock the cache directory %s", err)
+	}
+	defer lock.unlock()
+
+	for _, args := range d.goGetArgs() {
+		err = d.retry("download the dependencies", func() error {
+			return d.exec(args)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fyneBundle bundles the assets via fyne bundle.
+// The command output is written to the bundled go file
+func (d *Builder) fyneBundle() error {
+	args := d.fyneBundleArgs()
+	if d.verbose {
+		fmt.Fprintf(d.stdout, "docker %s\n", strings.Join(args, " "))
+	}
+
+	var stdout bytes.Buffer
+	cmd := d.docker(args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = d.stderr
+	err := cmd.Run()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(d.workDir, d.bundle.Output), stdout.Bytes(), 0644)
+}
+
+// goBuild runs the go build for target
+func (d *Builder) goBuild(target string) error {
+	args, err := d.goBuildArgs(target)
+	if err != nil {
+		return err
+	}
+
+	// create the target output folder, if any, to avoid it will be owned by the container user
+	targetOutput, err := d.targetOutput(target)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Join(d.workDir, "build", filepath.Dir(targetOutput)), 0755)
+	if err != nil {
+		return err
+	}
+
+	// prefix the output lines with the target
+	prefix := d.targetPrefix(target)
+	stdout := newPrefixWriter(d.stdout, prefix)
+	stderr := newPrefixWriter(d.stderr, prefix)
+	defer stdout.Flush()
+	defer stderr.Flush()
+	return d.execOutput(args, stdout, stderr)
+}
+
+// defaultArgs returns the default arguments used to run a docker container
+func (d *Builder) defaultArgs() []string {
+	args := []string{
+		"run",
+		"--rm",
+	}
+
+	// set workdir
+	args = append(args, "-w", d.appDir())
+
+	// mount root dir package, under image GOPATH/src when not a module
+	args = append(args, "-v", d.volume(d.workDir, d.appDir()))
+
+	// mount the cache user dir. Used to cache package dependencies (GOROOT/pkg and GOROOT/src)
+	// and the go build cache
+	args = append(args, "-v", d.volume(d.cacheDir+"/fyne-cross", "/go"))
+
+	// label the containers to remove them on cancellation, see removeContainers
+	if d.session != "" {
+		args = append(args, "--label", sessionLabel+"="+d.session)
+	}
+
+	// run the commands as the container user, if any. The container starts as root
+	// and the entrypoint drops the privileges once the container is set up
+	if u := d.containerUser(); u != "" {
+		args = append(args, "-e", "fyne_user="+u)
+	}
+
+	// set the module mode according to the project layout
+	if env := d.moduleEnv(); env != "" {
+		args = append(args, "-e", env)
+	}
+
+	// mount the custom CA certificates, if any
+	args = append(args, d.caCertsArgs()...)
+
+	// mount the additional volumes, if any
+	args = append(args, d.volumesArgs()...)
+
+	// export the env variables of the env file, of the env option and the proxy settings
+	args = append(args, d.envArgs()...)
+
+	// limit the container resources, if requested
+	if d.cpus != "" {
+		args = append(args, "--cpus", d.cpus)
+	}
+	if d.memory != "" {
+		args = append(args, "--memory", d.memory)
+	}
+
+	return args
+}
+
+// volume returns the volume argument used to mount the host path into the container
+// path with the specified options. The SELinux label, if any, is added to the options
+func (d *Builder) volume(hostPath string, containerPath string, opts ...string) string {
+	if d.selinux != "" {
+		opts = append(opts, d.selinux)
+	}
+	v := fmt.Sprintf("%s:%s", hostPath, containerPath)
+	if len(opts) > 0 {
+		v += ":" + strings.Join(opts, ",")
+	}
+	return v
+}
+
+// sessionArgs returns the arguments used to start the docker container session.
+// The network option applies to the session only, since the dependencies are downloaded
+// into dedicated containers, see goGet
+func (d *Builder) sessionArgs() []string {
+	args := append(d.defaultArgs(), "-d")
+	if d.network != "" {
+		args = append(args, "--network", d.network)
+	}
+	args = append(args, d.deltaArgs()...)
+	return append(args, d.moduleCacheArgs()...)
+}
+
+// moduleCacheArgs returns the arguments used to mount the module cache read-only.
+// Dependencies are downloaded by goGet
+func (d *Builder) moduleCacheArgs() []string {
+	return []string{"-v", d.volume(d.cacheDir+"/fyne-cross/pkg/mod", "/go/pkg/mod", "ro")}
+}
+
+// execArgs returns the arguments used to run the command into the container session
+// with the specified env variables. The command is passed as is, no shell is involved
+func (d *Builder) execArgs(env []string, command []string) []string {
+	args := []string{
+		"exec",
+	}
+
+	// run as the container user, if any
+	if u := d.containerUser(); u != "" {
+		args = append(args, "-u", u, "-e", "HOME="+containerHome)
+	}
+
+	for _, e := range env {
+		args = append(args, "-e", e)
+	}
+
+	args = append(args, d.containerID)
+
+	// kill the command and its child processes on timeout, since killing the docker exec
+	// client leaves the command running into the container session, see withTimeout
+	if d.timeout > 0 {
+		args = append(args, "timeout", "-s", "KILL", strconv.FormatFloat(d.timeout.Seconds(), 'f', -1, 64)+"s")
+	}
+	return append(args, command...)
+}
+
+// chownOutput gives the ownership of the build output folder back to the host user.
+// Files created into the container could be owned by root when the user id mapping fails.
+// Only Linux hosts are affected, Docker Desktop handles the ownership on macOS and Windows
+func (d *Builder) chownOutput() error {
+	if runtime.GOOS != "linux" {
+		return nil
+	}
+
+	// on rootless engines the container root user is mapped to the host user
+	uid, gid := "0", "0"
+	if !d.rootless {
+		u, err := user.Current()
+		if err != nil {
+			return err
+		}
+		uid, gid = u.Uid, u.Gid
+	}
+
+	return d.exec(d.chownArgs(uid, gid))
+}
+
+// chownArgs returns the arguments for the "chown" command on the build output folder.
+// The command runs as the container root user
+func (d *Builder) chownArgs(uid string, gid string) []string {
+	return []string{
+		"exec", d.containerID,
+		"chown", "-R", fmt.Sprintf("%s:%s", uid, gid), d.appDir() + "/build",
+	}
+}
+
+// fyneBundleArgs returns the arguments for the "fyne bundle" command
+func (d *Builder) fyneBundleArgs() []string {
+	bundleCmd := []string{"fyne", "bundle", "-package", d.bundle.Package, d.bundle.Dir}
+	return d.execArgs(nil, bundleCmd)
+}
+
+// goGetArgs returns the arguments of the commands downloading the dependencies, since the
+// module cache is mounted read-only into the session. In module mode the whole module graph
+// is downloaded via "go mod download", regardless of the build constraints, otherwise the
+// dependencies of each target are downloaded via "go get" with the target GOOS and GOARCH.
+// The commands run into a dedicated container with write access to the module cache
+func (d *Builder) goGetArgs() [][]string {
+	if d.gomod {
+		args := d.goGetContainerArgs(nil)
+		args = append(args, "mod", "download")
+		if d.verbose {
+			args = append(args, "-x")
+		}
+		return [][]string{args}
+	}
+
+	cmds := [][]string{}
+	for _, target := range d.targets {
+		parts := strings.Split(target, "/")
+		args := d.goGetContainerArgs([]string{"GOOS=" + parts[0], "GOARCH=" + parts[1], "CGO_ENABLED=1"})
+		args = append(args, "get")
+		if v := d.verbosityFlag(); v != "" {
+			args = append(args, v)
+		}
+		cmds = append(cmds, append(args, "-d", "./..."))
+	}
+	return cmds
+}
+
+// goGetContainerArgs returns the arguments used to run the go command downloading the
+// dependencies with the env variables
+func (d *Builder) goGetContainerArgs(env []string) []string {
+	args := d.defaultArgs()
+	for _, e := range append(d.goEnv(), env...) {
+		args = append(args, "-e", e)
+	}
+	return append(args, "-t", d.imageName(), d.goCmd())
+}
+
+// goBuildArgs returns the arguments for the "go build" command for target
+func (d *Builder) goBuildArgs(target string) ([]string, error) {
+	buildCmd, err := d.goBuildCmd(target)
+	if err != nil {
+		return []string{}, err
+	}
+	return d.execArgs(d.targetEnv(target), buildCmd), nil
+}
@@ -0,0 +1,153 @@
+package build
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// defaultEnvFile is the env file loaded, if exists, from the package root directory
+const defaultEnvFile = ".env"
+
+// envNameRegexp matches the name of an env variable
+var envNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// parseEnvFile parses the KEY=VALUE lines of an env file. Blank lines and lines starting with #
+// are ignored, the export prefix and the quotes surrounding the value are removed
+func parseEnvFile(r io.Reader) ([]string, error) {
+	env := []string{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		parts := strings.SplitN(line, "=", 2)
+		name := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || !envNameRegexp.MatchString(name) {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", n)
+		}
+
+		value := strings.TrimSpace(parts[1])
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		env = append(env, name+"="+value)
+	}
+	return env, scanner.Err()
+}
+
+// loadEnv returns the env variables exported into the containers: the variables of the env file
+// overridden by the explicit ones. The env file is relative to the work dir and defaults to
+// defaultEnvFile, loaded only if exists
+func loadEnv(workDir string, envFile string, explicit []string) ([]string, error) {
+	path := envFile
+	if path == "" {
+		path = defaultEnvFile
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(workDir, path)
+	}
+
+	env := []string{}
+	f, err := os.Open(path)
+	switch {
+	case err == nil:
+		env, err = parseEnvFile(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("Cannot parse the env file %s %s", path, err)
+		}
+	case os.IsNotExist(err) && envFile == "":
+		// the default env file is optional
+	default:
+		return nil, fmt.Errorf("Cannot open the env file %s", err)
+	}
+
+	for _, e := range explicit {
+		parts := strings.SplitN(e, "=", 2)
+		if len(parts) != 2 || !envNameRegexp.MatchString(parts[0]) {
+			return nil, fmt.Errorf("Invalid env variable %q, expected KEY=VALUE", e)
+		}
+		// the env file passed to docker has a variable per line
+		if strings.ContainsAny(parts[1], "\r\n") {
+			return nil, fmt.Errorf("Invalid env variable %q, multi-line values are not supported", parts[0])
+		}
+	}
+	return mergeEnv(env, explicit), nil
+}
+
+// mergeEnv merges the env variables, the latter ones take precedence.
+// The order of the first definition is kept
+func mergeEnv(envs ...[]string) []string {
+	merged := []string{}
+	index := map[string]int{}
+	for _, env := range envs {
+		for _, e := range env {
+			name := strings.SplitN(e, "=", 2)[0]
+			if i, ok := index[name]; ok {
+				merged[i] = e
+				continue
+			}
+			index[name] = len(merged)
+			merged = append(merged, e)
+		}
+	}
+	return merged
+}
+
+// writeEnvFile writes the env variables and the proxy settings to a temporary env file readable by
+// the current user only, passed to docker so that the values, i.e. the proxy credentials, are not
+// exposed on the command line and do not apply to the docker client itself, i.e. DOCKER_HOST.
+// The proxy settings are written last, so that they take precedence over the env variables.
+// The file must be removed via removeEnvFile
+func (d *Builder) writeEnvFile() error {
+	proxyEnv := d.proxy.env()
+	for _, e := range proxyEnv {
+		if strings.ContainsAny(e, "\r\n") {
+			return fmt.Errorf("Invalid proxy setting %q, multi-line values are not supported", strings.SplitN(e, "=", 2)[0])
+		}
+	}
+	env := append(append([]string{}, d.env...), proxyEnv...)
+	if len(env) == 0 {
+		return nil
+	}
+	f, err := ioutil.TempFile("", "fyne-cross-env")
+	if err != nil {
+		return fmt.Errorf("Cannot create the env file %s", err)
+	}
+	defer f.Close()
+	d.envFile = f.Name()
+
+	_, err = f.WriteString(strings.Join(env, "\n") + "\n")
+	if err != nil {
+		d.removeEnvFile()
+		return fmt.Errorf("Cannot write the env file %s", err)
+	}
+	return nil
+}
+
+// removeEnvFile removes the temporary env file, if any
+func (d *Builder) removeEnvFile() {
+	if d.envFile == "" {
+		return
+	}
+	os.Remove(d.envFile)
+	d.envFile = ""
+}
+
+// envArgs returns the arguments exporting the env variables into the container via the
+// temporary env file, see writeEnvFile
+func (d *Builder) envArgs() []string {
+	if d.envFile == "" {
+		return nil
+	}
+	return []string{"--env-file", d.envFile}
+}
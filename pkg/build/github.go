@@ -0,0 +1,109 @@
+package build
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// githubOutputDelimiter is the delimiter of the multiline step outputs
+const githubOutputDelimiter = "FYNE_CROSS_EOF"
+
+// githubOutputs returns the step outputs describing the artifacts in the $GITHUB_OUTPUT file format:
+//   - artifacts: the artifacts encoded as JSON
+//   - paths: the artifact paths, one per line
+//   - names: the artifact file names, one per line
+//   - checksums: the artifact checksums in the sha256sum format, one per line
+func githubOutputs(buildDir string, artifacts []Artifact) (string, error) {
+	b, err := json.Marshal(artifacts)
+	if err != nil {
+		return "", err
+	}
+
+	paths := []string{}
+	names := []string{}
+	checksums := []string{}
+	for _, a := range artifacts {
+		paths = append(paths, filepath.Join(buildDir, filepath.FromSlash(a.File)))
+		names = append(names, a.File)
+		checksums = append(checksums, fmt.Sprintf("%s  %s", a.SHA256, a.File))
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "artifacts=%s\n", b)
+	for _, o := range []struct {
+		name   string
+		values []string
+	}{
+		{"paths", paths},
+		{"names", names},
+		{"checksums", checksums},
+	} {
+		fmt.Fprintf(&buf, "%s<<%s\n", o.name, githubOutputDelimiter)
+		for _, v := range o.values {
+			fmt.Fprintln(&buf, v)
+		}
+		fmt.Fprintln(&buf, githubOutputDelimiter)
+	}
+	return buf.String(), nil
+}
+
+// writeGitHubOutput appends the step outputs describing the artifacts to the $GITHUB_OUTPUT file
+func (d *Builder) writeGitHubOutput(artifacts []Artifact) error {
+	path := os.Getenv("GITHUB_OUTPUT")
+	if path == "" {
+		return fmt.Errorf("The GITHUB_OUTPUT env variable is not set, is it running into a GitHub Actions workflow?")
+	}
+
+	outputs, err := githubOutputs(filepath.Join(d.workDir, "build"), artifacts)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = f.WriteString(outputs)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// escapeWorkflowData escapes the message of a GitHub Actions workflow command
+func escapeWorkflowData(s string) string {
+	s = strings.Replace(s, "%", "%25", -1)
+	s = strings.Replace(s, "\r", "%0D", -1)
+	return strings.Replace(s, "\n", "%0A", -1)
+}
+
+// startGroup prints the title of a step. When the GitHub output mode is enabled
+// the step output is folded into a group of the workflow log until endGroup is called
+func (d *Builder) startGroup(title string) {
+	if d.githubOutput {
+		fmt.Fprintf(d.stdout, "::group::%s\n", escapeWorkflowData(title))
+		return
+	}
+	fmt.Fprintln(d.stdout, title)
+}
+
+// endGroup ends the group started by startGroup
+func (d *Builder) endGroup() {
+	if d.githubOutput {
+		fmt.Fprintln(d.stdout, "::endgroup::")
+	}
+}
+
+// printError prints the error, as an error annotation when the GitHub output mode is enabled
+func (d *Builder) printError(err error) {
+	if d.githubOutput {
+		fmt.Fprintf(d.stdout, "::error::%s\n", escapeWorkflowData(err.Error()))
+		return
+	}
+	fmt.Fprintln(d.stdout, err)
+}
@@ -0,0 +1,41 @@
+package build
+
+import (
+	"testing"
+)
+
+func Test_githubOutputs(t *testing.T) {
+	artifacts := []Artifact{
+		{Target: "linux/amd64", File: "fyne-linux-amd64", Size: 4, SHA256: "0c06b5"},
+		{Target: "windows/amd64", File: "windows-amd64/fyne-windows-amd64.exe", Size: 4, SHA256: "1a2b3c"},
+	}
+	want := `artifacts=[{"target":"linux/amd64","file":"fyne-linux-amd64","size":4,"sha256":"0c06b5"},{"target":"windows/amd64","file":"windows-amd64/fyne-windows-amd64.exe","size":4,"sha256":"1a2b3c"}]
+paths<<FYNE_CROSS_EOF
+/app/build/fyne-linux-amd64
+/app/build/windows-amd64/fyne-windows-amd64.exe
+FYNE_CROSS_EOF
+names<<FYNE_CROSS_EOF
+fyne-linux-amd64
+windows-amd64/fyne-windows-amd64.exe
+FYNE_CROSS_EOF
+checksums<<FYNE_CROSS_EOF
+0c06b5  fyne-linux-amd64
+1a2b3c  windows-amd64/fyne-windows-amd64.exe
+FYNE_CROSS_EOF
+`
+	got, err := githubOutputs("/app/build", artifacts)
+	if err != nil {
+		t.Fatalf("githubOutputs() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("githubOutputs() = %v, want %v", got, want)
+	}
+}
+
+func Test_escapeWorkflowData(t *testing.T) {
+	got := escapeWorkflowData("Build failed 100%\nexit status 2")
+	want := "Build failed 100%25%0Aexit status 2"
+	if got != want {
+		t.Errorf("escapeWorkflowData() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,138 @@
+package build
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// buildHashes maps the targets to the hash of their build inputs. It is used to
+// skip the build of targets whose inputs have not changed since the last build
+type buildHashes map[string]string
+
+// loadBuildHashes loads the build hashes from path.
+// An empty map is returned if the file does not exist or is not valid
+func loadBuildHashes(path string) buildHashes {
+	hashes := buildHashes{}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return hashes
+	}
+	err = json.Unmarshal(b, &hashes)
+	if err != nil {
+		return buildHashes{}
+	}
+	return hashes
+}
+
+// save writes the build hashes to path
+func (h buildHashes) save(path string) error {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0644)
+}
+
+// sourceHash returns the hash of the files into dir, or of the file content when dir is a file.
+// The build output folder and the hidden files and folders are skipped
+func sourceHash(dir string) (string, error) {
+	h := sha256.New()
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." && info.IsDir() {
+			return nil
+		}
+
+		if rel == "build" || strings.HasPrefix(info.Name(), ".") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		fmt.Fprintf(h, "%s\x00", filepath.ToSlash(rel))
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(h, f)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashesFile returns the file used to store the build hashes of the work dir
+func (d *Builder) hashesFile() string {
+	h := sha256.Sum256([]byte(d.workDir))
+	return filepath.Join(d.cacheDir, "fyne-cross", "hashes", hex.EncodeToString(h[:8])+".json")
+}
+
+// hashKey returns the key of the build hashes for the target of the current package
+func (d *Builder) hashKey(target string) string {
+	return d.pkg + ":" + target
+}
+
+// inputsHash returns the hash of the build inputs shared by all the targets:
+// the work dir content, the content of the additional volumes and the docker image
+func (d *Builder) inputsHash() (string, error) {
+	src, err := sourceHash(d.workDir)
+	if err != nil {
+		return "", err
+	}
+	for _, m := range d.volumes {
+		v, err := sourceHash(m.hostPath)
+		if err != nil {
+			return "", err
+		}
+		src += m.containerPath + v
+	}
+	h := sha256.Sum256([]byte(src + d.imageDigest()))
+	return hex.EncodeToString(h[:]), nil
+}
+
+// targetHash returns the hash of the build inputs for target
+func (d *Builder) targetHash(target string, inputsHash string) (string, error) {
+	buildCmd, err := d.goBuildCmd(target)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	fmt.Fprintln(h, inputsHash)
+	for _, arg := range buildCmd {
+		fmt.Fprintln(h, arg)
+	}
+	for _, e := range d.targetEnv(target) {
+		fmt.Fprintln(h, e)
+	}
+	for _, e := range d.env {
+		fmt.Fprintln(h, e)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
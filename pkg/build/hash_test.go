@@ -0,0 +1,88 @@
+package build
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_sourceHash(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fyne-cross")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	write := func(name string, content string) {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		err := ioutil.WriteFile(path, []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("main.go", "package main")
+	h1, err := sourceHash(dir)
+	if err != nil {
+		t.Fatalf("sourceHash() error = %v", err)
+	}
+
+	write("build/fyne-example-linux-amd64", "binary")
+	write(".git/HEAD", "ref: refs/heads/master")
+	h2, _ := sourceHash(dir)
+	if h1 != h2 {
+		t.Errorf("sourceHash() changed on build output and hidden files update")
+	}
+
+	write("main.go", "package main\n")
+	h3, _ := sourceHash(dir)
+	if h1 == h3 {
+		t.Errorf("sourceHash() did not change on source file update")
+	}
+}
+
+func Test_buildHashes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fyne-cross")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "hashes", "test.json")
+	if got := loadBuildHashes(path); !reflect.DeepEqual(got, buildHashes{}) {
+		t.Errorf("loadBuildHashes() = %v, want empty", got)
+	}
+
+	want := buildHashes{"linux/amd64": "abc"}
+	err = want.save(path)
+	if err != nil {
+		t.Fatalf("buildHashes.save() error = %v", err)
+	}
+	if got := loadBuildHashes(path); !reflect.DeepEqual(got, want) {
+		t.Errorf("loadBuildHashes() = %v, want %v", got, want)
+	}
+}
+
+func TestBuilder_targetHash(t *testing.T) {
+	d := &Builder{
+		pkg: "fyne-io/fyne-example",
+	}
+	h1, err := d.targetHash("linux/amd64", "inputs")
+	if err != nil {
+		t.Fatalf("Builder.targetHash() error = %v", err)
+	}
+
+	h2, _ := d.targetHash("windows/amd64", "inputs")
+	if h1 == h2 {
+		t.Errorf("Builder.targetHash() must differ per target")
+	}
+
+	d.ldflags = "-X main.version=1.0.0"
+	h3, _ := d.targetHash("linux/amd64", "inputs")
+	if h1 == h3 {
+		t.Errorf("Builder.targetHash() must differ per build options")
+	}
+}
@@ -0,0 +1,122 @@
+package build
+
+import (
+	"fmt"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// iconsDir is the folder of the generated icons relative to the build folder
+const iconsDir = "icons"
+
+// minIconSize is the minimum size of the source icon, that is the largest size of the generated icons
+const minIconSize = 512
+
+// hicolorSizes are the sizes of the hicolor icon theme set generated for the linux desktop files
+var hicolorSizes = []int{16, 32, 48, 64, 128, 256, 512}
+
+// icnsSizes are the sizes embedded into the macOS icns icon. They must be a subset of hicolorSizes
+// since the hicolor icons are used as source, see iconCommands
+var icnsSizes = []int{16, 32, 48, 128, 256, 512}
+
+// icoSizes are the sizes embedded into the Windows ico icon
+var icoSizes = []int{256, 128, 64, 48, 32, 16}
+
+// checkIcon checks the icon is a square PNG image of at least minIconSize pixels located into the
+// work dir, so that it is available into the container. The path relative to the work dir is returned
+func checkIcon(workDir string, icon string) (string, error) {
+	path := icon
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(workDir, path)
+	}
+	rel, err := filepath.Rel(workDir, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("The icon %s must be located into the package root directory %s", icon, workDir)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("Cannot open the icon %s", err)
+	}
+	defer f.Close()
+
+	cfg, err := png.DecodeConfig(f)
+	if err != nil {
+		return "", fmt.Errorf("The icon %s is not a valid PNG image %s", icon, err)
+	}
+	if cfg.Width != cfg.Height || cfg.Width < minIconSize {
+		return "", fmt.Errorf("The icon %s must be a square image of at least %dx%d pixels, got %dx%d", icon, minIconSize, minIconSize, cfg.Width, cfg.Height)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// hicolorIcon returns the path of the hicolor icon of the specified size relative to the build folder
+func hicolorIcon(name string, size int) string {
+	return fmt.Sprintf("%s/hicolor/%dx%d/apps/%s.png", iconsDir, size, size, name)
+}
+
+// iconCommands returns the commands generating the icons for the targets from the icon
+// via ImageMagick and icnsutils: the hicolor set for linux, also used as source of the icns,
+// the icns for darwin and the multi-size ico for windows.
+// Paths are relative to the package root directory
+func iconCommands(icon string, name string, targets []string) [][]string {
+	goos := map[string]bool{}
+	for _, target := range targets {
+		goos[strings.Split(target, "/")[0]] = true
+	}
+
+	cmds := [][]string{}
+	if goos["linux"] || goos["darwin"] {
+		for _, size := range hicolorSizes {
+			cmds = append(cmds, []string{
+				"convert", icon, "-resize", fmt.Sprintf("%dx%d", size, size), "build/" + hicolorIcon(name, size),
+			})
+		}
+	}
+
+	if goos["darwin"] {
+		cmd := []string{"png2icns", fmt.Sprintf("build/%s/%s.icns", iconsDir, name)}
+		for _, size := range icnsSizes {
+			cmd = append(cmd, "build/"+hicolorIcon(name, size))
+		}
+		cmds = append(cmds, cmd)
+	}
+
+	if goos["windows"] {
+		sizes := []string{}
+		for _, size := range icoSizes {
+			sizes = append(sizes, fmt.Sprint(size))
+		}
+		cmds = append(cmds, []string{
+			"convert", icon, "-define", "icon:auto-resize=" + strings.Join(sizes, ","), fmt.Sprintf("build/%s/%s.ico", iconsDir, name),
+		})
+	}
+	return cmds
+}
+
+// generateIcons generates the platform specific icons of the current package into the
+// build/icons folder, see iconCommands
+func (d *Builder) generateIcons() error {
+	name, err := d.outputName()
+	if err != nil {
+		return err
+	}
+
+	// create the icon folders to avoid they will be owned by the container user
+	for _, size := range hicolorSizes {
+		err = os.MkdirAll(filepath.Join(d.workDir, "build", filepath.Dir(filepath.FromSlash(hicolorIcon(name, size)))), 0755)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, cmd := range iconCommands(d.icon, name, d.targets) {
+		err = d.exec(d.execArgs(nil, cmd))
+		if err != nil {
+			return fmt.Errorf("Cannot generate the icons %s", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package build
+
+import (
+	"image"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_checkIcon(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fyne-cross")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writePNG := func(name string, width int, height int) {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		err = png.Encode(f, image.NewRGBA(image.Rect(0, 0, width, height)))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	writePNG("assets/icon.png", 1024, 1024)
+	writePNG("small.png", 256, 256)
+	writePNG("wide.png", 1024, 512)
+	ioutil.WriteFile(filepath.Join(dir, "icon.svg"), []byte("<svg></svg>"), 0644)
+
+	tests := []struct {
+		name    string
+		icon    string
+		want    string
+		wantErr bool
+	}{
+		{name: "relative path", icon: "assets/icon.png", want: "assets/icon.png"},
+		{name: "absolute path", icon: filepath.Join(dir, "assets", "icon.png"), want: "assets/icon.png"},
+		{name: "too small", icon: "small.png", wantErr: true},
+		{name: "not square", icon: "wide.png", wantErr: true},
+		{name: "not a png", icon: "icon.svg", wantErr: true},
+		{name: "not found", icon: "missing.png", wantErr: true},
+		{name: "outside the package root", icon: "../icon.png", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := checkIcon(dir, tt.icon)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkIcon() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("checkIcon() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_iconCommands(t *testing.T) {
+	tests := []struct {
+		name    string
+		targets []string
+		want    [][]string
+	}{
+		{
+			name:    "windows",
+			targets: []string{"windows/amd64", "windows/386"},
+			want: [][]string{
+				{"convert", "icon.png", "-define", "icon:auto-resize=256,128,64,48,32,16", "build/icons/myapp.ico"},
+			},
+		},
+		{
+			name:    "darwin",
+			targets: []string{"darwin/amd64"},
+			want: [][]string{
+				{"convert", "icon.png", "-resize", "16x16", "build/icons/hicolor/16x16/apps/myapp.png"},
+				{"convert", "icon.png", "-resize", "32x32", "build/icons/hicolor/32x32/apps/myapp.png"},
+				{"convert", "icon.png", "-resize", "48x48", "build/icons/hicolor/48x48/apps/myapp.png"},
+				{"convert", "icon.png", "-resize", "64x64", "build/icons/hicolor/64x64/apps/myapp.png"},
+				{"convert", "icon.png", "-resize", "128x128", "build/icons/hicolor/128x128/apps/myapp.png"},
+				{"convert", "icon.png", "-resize", "256x256", "build/icons/hicolor/256x256/apps/myapp.png"},
+				{"convert", "icon.png", "-resize", "512x512", "build/icons/hicolor/512x512/apps/myapp.png"},
+				{
+					"png2icns", "build/icons/myapp.icns",
+					"build/icons/hicolor/16x16/apps/myapp.png",
+					"build/icons/hicolor/32x32/apps/myapp.png",
+					"build/icons/hicolor/48x48/apps/myapp.png",
+					"build/icons/hicolor/128x128/apps/myapp.png",
+					"build/icons/hicolor/256x256/apps/myapp.png",
+					"build/icons/hicolor/512x512/apps/myapp.png",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := iconCommands("icon.png", "myapp", tt.targets); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("iconCommands() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package build
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// derivedImageRepo is the repository of the images derived from the fyne-cross image
+// with the extra system packages installed
+const derivedImageRepo = "fyne-cross-derived"
+
+// entrypointLabel is the image label with the version of the entrypoint features fyne-cross relies on,
+// the ready file and the switch to the fyne_user user, see the Dockerfile
+const entrypointLabel = "io.fyne-cross.entrypoint"
+
+// entrypointVersion is the entrypoint version required by this release
+const entrypointVersion = "1"
+
+// aptPackageRegexp matches a debian package name with the optional architecture and version
+var aptPackageRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9+.\-]*(:[a-z0-9\-]+)?(=[A-Za-z0-9.+~:\-]+)?$`)
+
+// checkAptPackages checks the names of the extra system packages
+func checkAptPackages(pkgs []string) error {
+	for _, pkg := range pkgs {
+		if !aptPackageRegexp.MatchString(pkg) {
+			return fmt.Errorf("Invalid apt package %q", pkg)
+		}
+	}
+	return nil
+}
+
+// derivedImageName returns the name of the image derived from base with the packages installed.
+// The tag is the hash of the base image ID, if known, and of the sorted packages,
+// so that the derived image is reused until one of them changes
+func derivedImageName(base string, baseID string, pkgs []string) string {
+	sorted := append([]string{}, pkgs...)
+	sort.Strings(sorted)
+
+	h := sha256.New()
+	fmt.Fprintln(h, base)
+	fmt.Fprintln(h, baseID)
+	for _, pkg := range sorted {
+		fmt.Fprintln(h, pkg)
+	}
+	return derivedImageRepo + ":" + hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+// derivedDockerfile returns the Dockerfile of the image derived from base with the packages installed
+func derivedDockerfile(base string, pkgs []string) string {
+	return fmt.Sprintf(`FROM %s
+RUN apt-get update -qq \
+    && apt-get install -y -q --no-install-recommends %s \
+    && apt-get clean \
+    && rm -r /var/lib/apt/lists/*
+`, base, strings.Join(pkgs, " "))
+}
+
+// pullImage pulls the image used to run the containers when it is not available locally.
+// The image is pulled explicitly, instead of by docker run, to report the pull progress
+func (d *Builder) pullImage() error {
+	name := d.imageName()
+	err := d.docker("image", "inspect", name).Run()
+	if err == nil {
+		return nil
+	}
+
+	_, err = d.step(fmt.Sprintf("Pulling the image %s", name), func() error {
+		return d.retry("pull the image", func() error {
+			cmd := d.docker("pull", name)
+			cmd.Stdout = d.stdout
+			cmd.Stderr = d.stderr
+			return cmd.Run()
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("Cannot pull the image %s %s", name, err)
+	}
+	return nil
+}
+
+// ensureImage pulls the image, if needed, and builds the derived image when extra system packages
+// are requested setting it as the image used to run the containers. The derived image is built
+// only once, see derivedImageName
+func (d *Builder) ensureImage() error {
+	err := d.pullImage()
+	if err != nil {
+		return err
+	}
+
+	err = d.checkImage()
+	if err != nil {
+		return err
+	}
+
+	if len(d.aptPackages) == 0 {
+		return nil
+	}
+
+	base := d.imageName()
+	baseID := ""
+	out, err := d.docker("image", "inspect", "--format", "{{.Id}}", base).Output()
+	if err == nil {
+		baseID = strings.TrimSpace(string(out))
+	}
+
+	name := derivedImageName(base, baseID, d.aptPackages)
+	err = d.docker("image", "inspect", name).Run()
+	if err == nil {
+		d.image = name
+		return nil
+	}
+
+	_, err = d.step(fmt.Sprintf("Installing %s into %s", strings.Join(d.aptPackages, ", "), name), func() error {
+		args := []string{"build", "-t", name, "-"}
+		if d.verbose {
+			fmt.Fprintf(d.stdout, "docker %s\n", strings.Join(args, " "))
+		}
+		cmd := d.docker(args...)
+		cmd.Stdin = bytes.NewBufferString(derivedDockerfile(base, d.aptPackages))
+		cmd.Stdout = d.stdout
+		cmd.Stderr = d.stderr
+		return cmd.Run()
+	})
+	if err != nil {
+		return fmt.Errorf("Cannot build the image with the extra apt packages %s", err)
+	}
+	d.image = name
+	return nil
+}
+
+// checkImage checks the image used to run the containers supports the entrypoint features, so that
+// an outdated image fails fast instead of on the timeout waiting for the container session
+func (d *Builder) checkImage() error {
+	name := d.imageName()
+	out, err := d.docker("image", "inspect", "--format", fmt.Sprintf("{{index .Config.Labels %q}}", entrypointLabel), name).Output()
+	if err != nil {
+		return fmt.Errorf("Cannot inspect the image %s %s", name, err)
+	}
+	return checkEntrypointVersion(name, strings.TrimSpace(string(out)))
+}
+
+// checkEntrypointVersion checks the entrypoint version of the image name is the required one
+func checkEntrypointVersion(name string, version string) error {
+	if version != entrypointVersion {
+		return fmt.Errorf("The image %s is outdated, pull the newer image via docker pull %s or rebuild it via fyne-cross image build", name, name)
+	}
+	return nil
+}
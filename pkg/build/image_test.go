@@ -0,0 +1,80 @@
+package build
+
+import (
+	"testing"
+)
+
+func Test_checkAptPackages(t *testing.T) {
+	tests := []struct {
+		name    string
+		pkgs    []string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			pkgs: []string{"libvlc-dev", "portaudio19-dev", "libc6-dev:i386", "libgtk-3-dev=3.24.5-1", "g++"},
+		},
+		{
+			name:    "shell injection",
+			pkgs:    []string{"libvlc-dev; rm -rf /"},
+			wantErr: true,
+		},
+		{
+			name:    "option",
+			pkgs:    []string{"--allow-unauthenticated"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkAptPackages(tt.pkgs); (err != nil) != tt.wantErr {
+				t.Errorf("checkAptPackages() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_derivedImageName(t *testing.T) {
+	a := derivedImageName("lucor/fyne-cross", "sha256:1234", []string{"libvlc-dev", "portaudio19-dev"})
+	b := derivedImageName("lucor/fyne-cross", "sha256:1234", []string{"portaudio19-dev", "libvlc-dev"})
+	if a != b {
+		t.Errorf("derivedImageName() = %v, want %v: the packages order should not matter", b, a)
+	}
+
+	c := derivedImageName("lucor/fyne-cross", "sha256:5678", []string{"libvlc-dev", "portaudio19-dev"})
+	if a == c {
+		t.Errorf("derivedImageName() = %v: a base image change should change the name", c)
+	}
+}
+
+func Test_derivedDockerfile(t *testing.T) {
+	want := `FROM lucor/fyne-cross
+RUN apt-get update -qq \
+    && apt-get install -y -q --no-install-recommends libvlc-dev portaudio19-dev \
+    && apt-get clean \
+    && rm -r /var/lib/apt/lists/*
+`
+	if got := derivedDockerfile("lucor/fyne-cross", []string{"libvlc-dev", "portaudio19-dev"}); got != want {
+		t.Errorf("derivedDockerfile() = %v, want %v", got, want)
+	}
+}
+
+func Test_checkEntrypointVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		wantErr bool
+	}{
+		{name: "required version", version: entrypointVersion},
+		{name: "no label", version: "", wantErr: true},
+		{name: "no labels", version: "<no value>", wantErr: true},
+		{name: "other version", version: "0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkEntrypointVersion(DefaultImage, tt.version); (err != nil) != tt.wantErr {
+				t.Errorf("checkEntrypointVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package build
+
+import (
+	"os"
+)
+
+// lockFileName is the name of the lock file into the fyne-cross cache directory
+// synchronizing the writes to the module cache and to the toolchains folder
+const lockFileName = ".lock"
+
+// sessionLockFileName is the name of the lock file into the fyne-cross cache directory
+// held shared by the running builds and exclusive by the cache prune
+const sessionLockFileName = ".session.lock"
+
+// fileLock represents a lock held on a file. It is used to
+// synchronize the fyne-cross processes sharing the same cache directory
+type fileLock struct {
+	f *os.File
+}
+
+// lockFile acquires an exclusive lock on the file at path, creating it if
+// needed. It blocks until the lock is acquired
+func lockFile(path string) (*fileLock, error) {
+	return lockFileMode(path, true)
+}
+
+// lockFileShared acquires a shared lock on the file at path, creating it if
+// needed. It blocks until no exclusive lock is held
+func lockFileShared(path string) (*fileLock, error) {
+	return lockFileMode(path, false)
+}
+
+// lockFileMode acquires an exclusive or a shared lock on the file at path
+func lockFileMode(path string, exclusive bool) (*fileLock, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	err = lockFd(f, exclusive)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &fileLock{f: f}, nil
+}
+
+// unlock releases the lock
+func (l *fileLock) unlock() error {
+	err := unlockFd(l.f)
+	l.f.Close()
+	return err
+}
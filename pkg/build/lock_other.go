@@ -0,0 +1,18 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !windows
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!windows
+
+package build
+
+import (
+	"os"
+)
+
+// file locking is not supported, processes sharing the cache directory are not synchronized
+
+func lockFd(f *os.File, exclusive bool) error {
+	return nil
+}
+
+func unlockFd(f *os.File) error {
+	return nil
+}
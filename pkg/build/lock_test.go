@@ -0,0 +1,28 @@
+package build
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_lockFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fyne-cross")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, lockFileName)
+	for i := 0; i < 2; i++ {
+		lock, err := lockFile(path)
+		if err != nil {
+			t.Fatalf("lockFile() error = %v", err)
+		}
+		err = lock.unlock()
+		if err != nil {
+			t.Fatalf("fileLock.unlock() error = %v", err)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+//go:build windows
+// +build windows
+
+package build
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+const lockfileExclusiveLock = 0x00000002
+
+var (
+	kernel32         = syscall.NewLazyDLL("kernel32.dll")
+	procLockFileEx   = kernel32.NewProc("LockFileEx")
+	procUnlockFileEx = kernel32.NewProc("UnlockFileEx")
+)
+
+func lockFd(f *os.File, exclusive bool) error {
+	var flags uintptr
+	if exclusive {
+		flags = lockfileExclusiveLock
+	}
+	ol := new(syscall.Overlapped)
+	r, _, err := procLockFileEx.Call(f.Fd(), flags, 0, 1, 0, uintptr(unsafe.Pointer(ol)))
+	if r == 0 {
+		return err
+	}
+	return nil
+}
+
+func unlockFd(f *os.File) error {
+	ol := new(syscall.Overlapped)
+	r, _, err := procUnlockFileEx.Call(f.Fd(), 0, 1, 0, uintptr(unsafe.Pointer(ol)))
+	if r == 0 {
+		return err
+	}
+	return nil
+}
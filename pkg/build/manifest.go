@@ -0,0 +1,131 @@
+package build
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// manifestFile is the name of the build manifest written into the output folder
+const manifestFile = "build-manifest.json"
+
+// manifest describes the artifacts produced by a build along with the
+// provenance metadata of the build environment
+type manifest struct {
+	Version     string     `json:"version,omitempty"`
+	GoVersion   string     `json:"go_version"`
+	ImageDigest string     `json:"image_digest"`
+	GitCommit   string     `json:"git_commit"`
+	Timestamp   time.Time  `json:"timestamp"`
+	Artifacts   []Artifact `json:"artifacts"`
+}
+
+// Artifact describes a file produced by the build for a target
+type Artifact struct {
+	Target string `json:"target"`
+	// Package is the package built for target, if any
+	Package string `json:"package,omitempty"`
+	// File is the artifact path relative to the build folder
+	File   string `json:"file"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// newArtifact returns the artifact for the file at path built for target
+func newArtifact(target string, path string) (Artifact, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Artifact{}, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return Artifact{}, err
+	}
+
+	return Artifact{
+		Target: target,
+		File:   filepath.Base(path),
+		Size:   size,
+		SHA256: hex.EncodeToString(h.Sum(nil)),
+	}, nil
+}
+
+// targetArtifacts returns the artifacts of the built targets
+func (d *Builder) targetArtifacts(results []targetResult) ([]Artifact, error) {
+	artifacts := []Artifact{}
+	for _, res := range results {
+		a, err := newArtifact(res.target, filepath.Join(d.workDir, "build", res.output))
+		if err != nil {
+			return nil, fmt.Errorf("Cannot describe the artifact for target %s: %s", res.target, err)
+		}
+		// keep the target subdir, if any
+		a.File = res.output
+		a.Package = res.pkg
+		artifacts = append(artifacts, a)
+	}
+	return artifacts, nil
+}
+
+// writeManifest writes the build manifest describing the artifacts into the output folder
+func (d *Builder) writeManifest(artifacts []Artifact) error {
+	m := manifest{
+		Version:     d.version,
+		GoVersion:   d.goVersion(),
+		ImageDigest: d.imageDigest(),
+		GitCommit:   d.gitCommit(),
+		Timestamp:   time.Now().UTC(),
+		Artifacts:   artifacts,
+	}
+
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(d.workDir, "build", manifestFile), b, 0644)
+}
+
+// goVersion returns the version of the go toolchain of the container session.
+// An empty string is returned if it cannot be determined
+func (d *Builder) goVersion() string {
+	out, err := d.docker(d.execArgs(d.goEnv(), []string{d.goCmd(), "version"})...).Output()
+	if err != nil {
+		return ""
+	}
+	// output example: go version go1.12.6 linux/amd64
+	parts := strings.Fields(string(out))
+	if len(parts) < 3 {
+		return ""
+	}
+	return parts[2]
+}
+
+// imageDigest returns the repository digest of the docker image.
+// An empty string is returned if it cannot be determined
+func (d *Builder) imageDigest() string {
+	out, err := d.docker("image", "inspect", "--format", "{{index .RepoDigests 0}}", d.imageName()).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// gitCommit returns the git commit of the work dir.
+// An empty string is returned if the work dir is not a git repository
+func (d *Builder) gitCommit() string {
+	out, err := exec.Command("git", "-C", d.workDir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
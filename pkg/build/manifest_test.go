@@ -0,0 +1,69 @@
+package build
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_newArtifact(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fyne-cross")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "test-linux-amd64")
+	err = ioutil.WriteFile(path, []byte("fyne"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		target string
+		path   string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    Artifact
+		wantErr bool
+	}{
+		{
+			name: "existing file",
+			args: args{
+				target: "linux/amd64",
+				path:   path,
+			},
+			want: Artifact{
+				Target: "linux/amd64",
+				File:   "test-linux-amd64",
+				Size:   4,
+				SHA256: "0c06b56abb9e1d12061e6aeef8d7149a8b6b267787cd596469d394b33c57ef6a",
+			},
+		},
+		{
+			name: "missing file",
+			args: args{
+				target: "linux/amd64",
+				path:   filepath.Join(dir, "missing"),
+			},
+			want:    Artifact{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newArtifact(tt.args.target, tt.args.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newArtifact() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newArtifact() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
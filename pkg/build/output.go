@@ -0,0 +1,87 @@
+package build
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+)
+
+// targetColors are the ANSI colors of the target prefixes, assigned in the targets order
+var targetColors = []string{"36", "33", "35", "32", "34", "31"}
+
+// colorEnabled returns true if the output to w can be colored: w is a terminal
+// and the NO_COLOR env variable is not set, see https://no-color.org
+func colorEnabled(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// targetPrefix returns the prefix of the output lines of target, colored if enabled
+func (d *Builder) targetPrefix(target string) string {
+	prefix := fmt.Sprintf("[%s]", target)
+	if !d.color {
+		return prefix + " "
+	}
+	color := targetColors[0]
+	for i, t := range d.targets {
+		if t == target {
+			color = targetColors[i%len(targetColors)]
+			break
+		}
+	}
+	return fmt.Sprintf("\x1b[%sm%s\x1b[0m ", color, prefix)
+}
+
+// prefixWriter writes to w the lines prefixed by prefix. Each line is written with a single
+// write, so that the lines of different writers sharing w are not interleaved.
+// Flush must be called to write the last line not terminated by a newline, if any
+type prefixWriter struct {
+	w      io.Writer
+	prefix []byte
+	buf    []byte
+}
+
+// newPrefixWriter returns a prefixWriter writing to w the lines prefixed by prefix
+func newPrefixWriter(w io.Writer, prefix string) *prefixWriter {
+	return &prefixWriter{w: w, prefix: []byte(prefix)}
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.buf = append(p.buf, b...)
+	for {
+		i := bytes.IndexByte(p.buf, '\n')
+		if i < 0 {
+			break
+		}
+		err := p.writeLine(p.buf[:i+1])
+		if err != nil {
+			return len(b), err
+		}
+		p.buf = p.buf[i+1:]
+	}
+	return len(b), nil
+}
+
+// Flush writes the buffered line, if any, terminated by a newline
+func (p *prefixWriter) Flush() error {
+	if len(p.buf) == 0 {
+		return nil
+	}
+	err := p.writeLine(append(p.buf, '\n'))
+	p.buf = nil
+	return err
+}
+
+// writeLine writes the prefixed line
+func (p *prefixWriter) writeLine(line []byte) error {
+	_, err := p.w.Write(append(append([]byte{}, p.prefix...), line...))
+	return err
+}
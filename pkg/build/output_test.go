@@ -0,0 +1,64 @@
+package build
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestBuilder_targetPrefix(t *testing.T) {
+	tests := []struct {
+		name   string
+		color  bool
+		target string
+		want   string
+	}{
+		{
+			name:   "no color",
+			target: "windows/amd64",
+			want:   "[windows/amd64] ",
+		},
+		{
+			name:   "first target",
+			color:  true,
+			target: "linux/amd64",
+			want:   "\x1b[36m[linux/amd64]\x1b[0m ",
+		},
+		{
+			name:   "second target",
+			color:  true,
+			target: "windows/amd64",
+			want:   "\x1b[33m[windows/amd64]\x1b[0m ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Builder{
+				targets: []string{"linux/amd64", "windows/amd64"},
+				color:   tt.color,
+			}
+			if got := d.targetPrefix(tt.target); got != tt.want {
+				t.Errorf("Builder.targetPrefix() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_prefixWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := newPrefixWriter(&buf, "[linux/amd64] ")
+	fmt.Fprint(w, "# fyne-io/fyne-example\n./main.go:10:2: undefined: ")
+	fmt.Fprint(w, "foo\n")
+	fmt.Fprint(w, "exit status 2")
+
+	want := "[linux/amd64] # fyne-io/fyne-example\n[linux/amd64] ./main.go:10:2: undefined: foo\n"
+	if got := buf.String(); got != want {
+		t.Errorf("prefixWriter = %q, want %q", got, want)
+	}
+
+	w.Flush()
+	want += "[linux/amd64] exit status 2\n"
+	if got := buf.String(); got != want {
+		t.Errorf("prefixWriter.Flush() = %q, want %q", got, want)
+	}
+}
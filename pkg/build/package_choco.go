@@ -0,0 +1,93 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+)
+
+func init() {
+	RegisterPackager("choco", &ChocoPackager{})
+}
+
+// chocoArchs maps the targets to the architectures of the chocolatey install script
+var chocoArchs = map[string]string{
+	"windows/386":   "x86",
+	"windows/amd64": "x64",
+}
+
+// chocoNuspecTemplate is the template of the chocolatey package specification
+const chocoNuspecTemplate = `<?xml version="1.0" encoding="utf-8"?>
+<!-- Generated by fyne-cross -->
+<package xmlns="http://schemas.microsoft.com/packaging/2015/06/nuspec.xsd">
+  <metadata>
+    <id>{{lower .ID}}</id>
+    <version>{{.Version}}</version>
+    <title>{{html .Name}}</title>
+    <authors>{{html .Publisher}}</authors>
+{{- if .Homepage}}
+    <projectUrl>{{html .Homepage}}</projectUrl>
+{{- end}}
+    <summary>{{html .Description}}</summary>
+    <description>{{html .Description}}</description>
+  </metadata>
+  <files>
+    <file src="tools\**" target="tools" />
+  </files>
+</package>
+`
+
+// chocoInstallTemplate is the template of the chocolatey install script. The executable is
+// downloaded into the package folder, chocolatey creates the shim on install
+const chocoInstallTemplate = `# Generated by fyne-cross
+$ErrorActionPreference = 'Stop'
+$toolsDir = "$(Split-Path -parent $MyInvocation.MyCommand.Definition)"
+
+$packageArgs = @{
+  packageName    = $env:ChocolateyPackageName
+  fileFullPath   = Join-Path $toolsDir '{{.Command}}.exe'
+{{- range .Installers}}
+{{- if eq .Arch "x86"}}
+  url            = '{{.URL}}'
+  checksum       = '{{.SHA256}}'
+  checksumType   = 'sha256'
+{{- else}}
+  url64bit       = '{{.URL}}'
+  checksum64     = '{{.SHA256}}'
+  checksumType64 = 'sha256'
+{{- end}}
+{{- end}}
+}
+
+Get-ChocolateyWebFile @packageArgs
+`
+
+// ChocoPackager generates the chocolatey package sources of the windows executables.
+// The sources are written into the build/choco/<id> folder, ready for "choco pack".
+// The package identifier, publisher and description are required
+type ChocoPackager struct {
+	ReleaseInfo
+}
+
+// Package generates the package specification and the install script
+func (p *ChocoPackager) Package(ctx context.Context, in PluginInput) ([]Artifact, error) {
+	data, err := p.manifestData(in, chocoArchs)
+	if err != nil {
+		return nil, err
+	}
+	if data.Publisher == "" || data.Description == "" {
+		return nil, fmt.Errorf("The publisher and description are required by chocolatey, see FYNE_CROSS_PUBLISHER and FYNE_CROSS_DESCRIPTION")
+	}
+
+	dir := strings.ToLower(data.ID)
+	nuspec, err := data.writeManifest(in, "choco", "package.nuspec", chocoNuspecTemplate, path.Join(dir, dir+".nuspec"))
+	if err != nil {
+		return nil, err
+	}
+	install, err := data.writeManifest(in, "choco", "chocolateyinstall.ps1", chocoInstallTemplate, path.Join(dir, "tools", "chocolateyinstall.ps1"))
+	if err != nil {
+		return nil, err
+	}
+	return []Artifact{nuspec, install}, nil
+}
@@ -0,0 +1,93 @@
+package build
+
+import (
+	"context"
+	"path"
+	"strings"
+)
+
+func init() {
+	RegisterPackager("homebrew", &HomebrewPackager{})
+}
+
+// homebrewArchs maps the targets to the homebrew cask architectures
+var homebrewArchs = map[string]string{
+	"darwin/amd64": "intel",
+	"darwin/arm64": "arm",
+}
+
+// homebrewCaskTemplate is the template of the homebrew cask. The url and the binary are
+// declared per architecture when the executables of more architectures are built.
+// The localized names are listed as additional names, casks have no localized description
+const homebrewCaskTemplate = `# Generated by fyne-cross
+cask "{{caskToken .ID}}" do
+  version "{{.Version}}"
+{{- if eq (len .Installers) 1}}
+{{- with index .Installers 0}}
+  sha256 "{{.SHA256}}"
+
+  url "{{.URL}}"
+{{- end}}
+{{- else}}
+{{- range .Installers}}
+
+  on_{{.Arch}} do
+    sha256 "{{.SHA256}}"
+
+    url "{{.URL}}"
+  end
+{{- end}}
+{{- end}}
+
+  name {{printf "%q" .Name}}
+{{- range .Locales}}
+{{- if and .Name (ne .Name $.Name)}}
+  name {{printf "%q" .Name}}
+{{- end}}
+{{- end}}
+{{- if .Description}}
+  desc {{printf "%q" .Description}}
+{{- end}}
+{{- if .Homepage}}
+  homepage "{{.Homepage}}"
+{{- end}}
+{{- $command := .Command}}
+{{- if eq (len .Installers) 1}}
+
+  binary "{{(index .Installers 0).Name}}", target: "{{$command}}"
+{{- else}}
+{{- range .Installers}}
+
+  on_{{.Arch}} do
+    binary "{{.Name}}", target: "{{$command}}"
+  end
+{{- end}}
+{{- end}}
+end
+`
+
+// caskToken returns the cask token of the package identifier: lower case, with the dots
+// replaced by hyphens, i.e. fyneio-example
+func caskToken(id string) string {
+	return strings.Replace(strings.ToLower(id), ".", "-", -1)
+}
+
+// HomebrewPackager generates the homebrew cask of the darwin executables.
+// The cask is written into the build/homebrew/Casks folder, ready for a tap repository
+type HomebrewPackager struct {
+	ReleaseInfo
+}
+
+// Package generates the cask
+func (p *HomebrewPackager) Package(ctx context.Context, in PluginInput) ([]Artifact, error) {
+	data, err := p.manifestData(in, homebrewArchs)
+	if err != nil {
+		return nil, err
+	}
+
+	cask, err := data.writeManifest(in, "homebrew", "cask.rb", homebrewCaskTemplate, path.Join("Casks", caskToken(data.ID)+".rb"))
+	if err != nil {
+		return nil, err
+	}
+	return []Artifact{cask}, nil
+}
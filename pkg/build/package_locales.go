@@ -0,0 +1,239 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+func init() {
+	RegisterPackager("locales", &LocalesPackager{})
+}
+
+// defaultLocale is the locale of the default metadata, i.e. FYNE_CROSS_APP_NAME and FYNE_CROSS_DESCRIPTION
+const defaultLocale = "en-US"
+
+// localesDesktopTemplate is the template of the freedesktop entry of the linux and freebsd packages
+const localesDesktopTemplate = `# Generated by fyne-cross
+[Desktop Entry]
+Type=Application
+Name={{.Name}}
+{{- range .Locales}}{{if .Name}}
+Name[{{desktopLocale .Locale}}]={{.Name}}
+{{- end}}{{end}}
+{{- if .Description}}
+Comment={{.Description}}
+{{- end}}
+{{- range .Locales}}{{if .Description}}
+Comment[{{desktopLocale .Locale}}]={{.Description}}
+{{- end}}{{end}}
+Exec={{.Command}}
+Icon={{.Command}}
+`
+
+// localesPlistTemplate is the template of the Info.plist of the darwin app bundle
+const localesPlistTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<!-- Generated by fyne-cross -->
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>CFBundleDevelopmentRegion</key>
+	<string>en</string>
+	<key>CFBundleDisplayName</key>
+	<string>{{html .Name}}</string>
+	<key>CFBundleExecutable</key>
+	<string>{{html .Command}}</string>
+	<key>CFBundleIdentifier</key>
+	<string>{{html .ID}}</string>
+	<key>CFBundleName</key>
+	<string>{{html .Name}}</string>
+	<key>CFBundlePackageType</key>
+	<string>APPL</string>
+	<key>CFBundleShortVersionString</key>
+	<string>{{html .Version}}</string>
+{{- if .Locales}}
+	<key>CFBundleLocalizations</key>
+	<array>
+		<string>en</string>
+{{- range .Locales}}
+		<string>{{.Locale}}</string>
+{{- end}}
+	</array>
+{{- end}}
+</dict>
+</plist>
+`
+
+// localesStringsTemplate is the template of the InfoPlist.strings of a locale of the darwin app bundle
+const localesStringsTemplate = `/* Generated by fyne-cross */
+"CFBundleName" = {{printf "%q" .Locale.Name}};
+"CFBundleDisplayName" = {{printf "%q" .Locale.Name}};
+`
+
+// localesWxlTemplate is the template of the WiX localization of a culture of the windows MSI package
+const localesWxlTemplate = `<?xml version="1.0" encoding="utf-8"?>
+<!-- Generated by fyne-cross -->
+<WixLocalization Culture="{{.Locale.Locale}}" xmlns="http://schemas.microsoft.com/wix/2006/localization">
+  <String Id="ProductName">{{html .Locale.Name}}</String>
+  <String Id="ProductDescription">{{html .Locale.Description}}</String>
+</WixLocalization>
+`
+
+// desktopLocale returns the freedesktop locale of the language tag, i.e. de_DE for de-DE
+func desktopLocale(locale string) string {
+	return strings.Replace(locale, "-", "_", -1)
+}
+
+// localeFile represents a localized metadata file written by the locales packager
+type localeFile struct {
+	// name is the template name
+	name string
+	// builtin is the builtin template
+	builtin string
+	// file is the file path, relative to the GOOS folder
+	file string
+	// locale is the localized metadata the template is executed with, if any
+	locale ManifestLocale
+}
+
+// LocalesPackager generates the localized metadata for the platform packages of the built executables
+// from the locales file, see ReleaseInfo.LocalesFile. The files are written into the build/locales/<goos>
+// folder: the .desktop entry for linux and freebsd, the Info.plist and the InfoPlist.strings of each
+// locale following the app bundle Contents layout for darwin and the WiX localization of each culture
+// for windows. The translation catalogs, see ReleaseInfo.TranslationsDir, are copied alongside.
+// fyne-cross does not build the deb, AppImage, app bundle or MSI packages, so the files are the
+// inputs of the packaging tools, they are not added to any package.
+// The package identifier and version are required
+type LocalesPackager struct {
+	ReleaseInfo
+}
+
+// Package generates the localized metadata for each GOOS of the built executables
+func (p *LocalesPackager) Package(ctx context.Context, in PluginInput) ([]Artifact, error) {
+	data, err := p.releaseData(in.WorkDir)
+	if err != nil {
+		return nil, err
+	}
+	if data.ID == "" || data.Version == "" {
+		return nil, fmt.Errorf("The package identifier and version are required, see FYNE_CROSS_APP_ID and FYNE_CROSS_APP_VERSION")
+	}
+	for _, l := range data.Locales {
+		if l.Locale == defaultLocale {
+			return nil, fmt.Errorf("The en-US metadata is the default locale, see FYNE_CROSS_APP_NAME and FYNE_CROSS_DESCRIPTION")
+		}
+	}
+
+	goosList := []string{}
+	installers := map[string][]ManifestInstaller{}
+	for _, a := range in.Artifacts {
+		if a.Target == "" || a.Package == "" {
+			continue
+		}
+		goos := strings.Split(a.Target, "/")[0]
+		if _, ok := installers[goos]; !ok {
+			goosList = append(goosList, goos)
+		}
+		installers[goos] = append(installers[goos], ManifestInstaller{Target: a.Target, Name: assetName(a.File), SHA256: a.SHA256})
+	}
+
+	artifacts := []Artifact{}
+	for _, goos := range goosList {
+		files, translations := data.localeFiles(goos)
+		if files == nil {
+			continue
+		}
+		gd := data
+		gd.Installers = installers[goos]
+		for _, f := range files {
+			fd := gd
+			fd.Locale = f.locale
+			a, err := fd.writeManifest(in, "locales", f.name, f.builtin, path.Join(goos, f.file))
+			if err != nil {
+				return nil, err
+			}
+			artifacts = append(artifacts, a)
+		}
+		if gd.TranslationsDir == "" {
+			continue
+		}
+		copied, err := gd.copyTranslations(in, path.Join(goos, translations))
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, copied...)
+	}
+	return artifacts, nil
+}
+
+// localeFiles returns the localized metadata files of the GOOS packages and the folder of the
+// translation catalogs, relative to the GOOS folder. The files are nil if the GOOS is not supported
+func (d ManifestData) localeFiles(goos string) ([]localeFile, string) {
+	switch goos {
+	case "linux", "freebsd":
+		return []localeFile{
+			{name: "app.desktop", builtin: localesDesktopTemplate, file: d.Command + ".desktop"},
+		}, "translations"
+	case "darwin":
+		files := []localeFile{
+			{name: "Info.plist", builtin: localesPlistTemplate, file: "Info.plist"},
+		}
+		for _, l := range d.Locales {
+			if l.Name == "" {
+				continue
+			}
+			files = append(files, localeFile{name: "InfoPlist.strings", builtin: localesStringsTemplate, file: path.Join("Resources", l.Locale+".lproj", "InfoPlist.strings"), locale: l})
+		}
+		return files, path.Join("Resources", "translations")
+	case "windows":
+		locales := append([]ManifestLocale{{Locale: defaultLocale}}, d.Locales...)
+		files := []localeFile{}
+		for _, l := range locales {
+			if l.Name == "" {
+				l.Name = d.Name
+			}
+			if l.Description == "" {
+				l.Description = d.Description
+			}
+			files = append(files, localeFile{name: "culture.wxl", builtin: localesWxlTemplate, file: l.Locale + ".wxl", locale: l})
+		}
+		return files, "translations"
+	}
+	return nil, ""
+}
+
+// copyTranslations copies the translation catalogs of the translations folder into dir,
+// relative to the packager folder of the build folder
+func (d ManifestData) copyTranslations(in PluginInput, dir string) ([]Artifact, error) {
+	entries, err := ioutil.ReadDir(d.TranslationsDir)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot read the translations folder %s", err)
+	}
+
+	artifacts := []Artifact{}
+	for _, e := range entries {
+		if !e.Mode().IsRegular() {
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(d.TranslationsDir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("Cannot read the translation catalog %s", err)
+		}
+		rel := path.Join("locales", dir, e.Name())
+		dest := filepath.Join(in.BuildDir, filepath.FromSlash(rel))
+		err = os.MkdirAll(filepath.Dir(dest), 0755)
+		if err != nil {
+			return nil, err
+		}
+		err = ioutil.WriteFile(dest, b, 0644)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(in.Stdout, "Copied %s\n", rel)
+		artifacts = append(artifacts, Artifact{Target: d.Installers[0].Target, File: rel})
+	}
+	return artifacts, nil
+}
@@ -0,0 +1,270 @@
+package build
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// localeRegexp matches a BCP 47 language tag, i.e. de or pt-BR
+var localeRegexp = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+// ReleaseInfo represents the release metadata of the package manager manifests generated by
+// the winget, choco and homebrew packagers and of the localized metadata generated by the locales
+// packager. Unset fields default to the FYNE_CROSS_* env variables. The relative paths are
+// resolved against the project folder
+type ReleaseInfo struct {
+	// ID is the package identifier, i.e. FyneIO.Example for winget. Default to $FYNE_CROSS_APP_ID
+	ID string
+	// Name is the application name. Default to $FYNE_CROSS_APP_NAME or the package identifier
+	Name string
+	// Command is the name of the installed executable. Default to $FYNE_CROSS_APP_COMMAND or
+	// the last dot separated part of the package identifier in lower case, i.e. example
+	Command string
+	// Version is the release version. Default to $FYNE_CROSS_APP_VERSION or the release tag,
+	// $GITHUB_REF_NAME or $CI_COMMIT_TAG, without the v prefix
+	Version string
+	// Publisher is the application publisher. Default to $FYNE_CROSS_PUBLISHER
+	Publisher string
+	// Description is the short description of the application. Default to $FYNE_CROSS_DESCRIPTION
+	Description string
+	// Homepage is the application homepage. Default to $FYNE_CROSS_HOMEPAGE
+	Homepage string
+	// License is the application license, i.e. BSD-3-Clause. Default to $FYNE_CROSS_LICENSE
+	License string
+	// URL is the template of the download URL of the artifacts, executed with the Version, Tag
+	// and Name fields, the latter being the release asset name of the artifact.
+	// Default to $FYNE_CROSS_RELEASE_URL or, on GitHub Actions, to the URL of the release
+	// assets uploaded by the github publisher
+	URL string
+	// TemplateDir is the folder of the custom manifest templates, looked up as
+	// <packager>/<template>.tmpl, i.e. homebrew/cask.rb.tmpl.
+	// Default to $FYNE_CROSS_TEMPLATE_DIR, the built-in templates are used when not found
+	TemplateDir string
+	// LocalesFile is the JSON file of the application name and description by locale, i.e.
+	// {"de-DE": {"name": "Fyne Beispiel", "description": "Die Fyne Beispielanwendung"}}.
+	// Default to $FYNE_CROSS_LOCALES
+	LocalesFile string
+	// TranslationsDir is the folder of the translation catalogs copied by the locales packager,
+	// i.e. the translations/en.json and translations/de.json catalogs of the fyne lang package.
+	// Default to $FYNE_CROSS_TRANSLATIONS_DIR
+	TranslationsDir string
+}
+
+// ManifestLocale represents the application metadata localized for a locale
+type ManifestLocale struct {
+	// Locale is the BCP 47 language tag, i.e. de-DE
+	Locale      string `json:"-"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// ManifestInstaller represents an artifact referenced by a package manager manifest
+type ManifestInstaller struct {
+	// Target is the target of the artifact, i.e. windows/amd64
+	Target string
+	// Arch is the architecture of the target, named as expected by the package manager
+	Arch string
+	// Name is the release asset name of the artifact
+	Name string
+	// URL is the download URL of the artifact
+	URL string
+	// SHA256 is the artifact checksum
+	SHA256 string
+}
+
+// ManifestData represents the data the manifest templates are executed with
+type ManifestData struct {
+	ReleaseInfo
+	// Tag is the release tag
+	Tag string
+	// Installers are the artifacts referenced by the manifest
+	Installers []ManifestInstaller
+	// Locales are the localized metadata sorted by locale, see ReleaseInfo.LocalesFile
+	Locales []ManifestLocale
+	// Locale is the localized metadata of the per-locale manifest being written, if any
+	Locale ManifestLocale
+}
+
+// manifestFuncs are the functions available to the manifest templates
+var manifestFuncs = template.FuncMap{
+	"lower":         strings.ToLower,
+	"caskToken":     caskToken,
+	"desktopLocale": desktopLocale,
+}
+
+// releaseData returns the release metadata with the unset fields set to their default and the
+// localized metadata of the locales file. The relative paths are resolved against workDir
+func (r ReleaseInfo) releaseData(workDir string) (ManifestData, error) {
+	data := ManifestData{
+		ReleaseInfo: ReleaseInfo{
+			ID:              envOr(r.ID, "FYNE_CROSS_APP_ID"),
+			Name:            envOr(r.Name, "FYNE_CROSS_APP_NAME"),
+			Command:         envOr(r.Command, "FYNE_CROSS_APP_COMMAND"),
+			Publisher:       envOr(r.Publisher, "FYNE_CROSS_PUBLISHER"),
+			Description:     envOr(r.Description, "FYNE_CROSS_DESCRIPTION"),
+			Homepage:        envOr(r.Homepage, "FYNE_CROSS_HOMEPAGE"),
+			License:         envOr(r.License, "FYNE_CROSS_LICENSE"),
+			URL:             envOr(r.URL, "FYNE_CROSS_RELEASE_URL"),
+			TemplateDir:     projectPath(workDir, envOr(r.TemplateDir, "FYNE_CROSS_TEMPLATE_DIR")),
+			LocalesFile:     projectPath(workDir, envOr(r.LocalesFile, "FYNE_CROSS_LOCALES")),
+			TranslationsDir: projectPath(workDir, envOr(r.TranslationsDir, "FYNE_CROSS_TRANSLATIONS_DIR")),
+		},
+		Tag: envOr("", "GITHUB_REF_NAME", "CI_COMMIT_TAG"),
+	}
+	data.Version = envOr(r.Version, "FYNE_CROSS_APP_VERSION")
+	if data.Version == "" {
+		data.Version = strings.TrimPrefix(data.Tag, "v")
+	}
+	if data.Tag == "" {
+		data.Tag = "v" + data.Version
+	}
+	if data.Name == "" {
+		data.Name = data.ID
+	}
+	if data.Command == "" {
+		parts := strings.Split(data.ID, ".")
+		data.Command = strings.ToLower(parts[len(parts)-1])
+	}
+	if data.URL == "" && os.Getenv("GITHUB_REPOSITORY") != "" {
+		server := envOr("", "GITHUB_SERVER_URL")
+		if server == "" {
+			server = "https://github.com"
+		}
+		data.URL = fmt.Sprintf("%s/%s/releases/download/{{.Tag}}/{{.Name}}", server, os.Getenv("GITHUB_REPOSITORY"))
+	}
+
+	if data.LocalesFile != "" {
+		locales, err := readLocales(data.LocalesFile)
+		if err != nil {
+			return data, err
+		}
+		data.Locales = locales
+	}
+	return data, nil
+}
+
+// projectPath returns the path p resolved against the project folder workDir, if relative
+func projectPath(workDir string, p string) string {
+	if p == "" || workDir == "" || filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(workDir, p)
+}
+
+// manifestData returns the data of the manifest referencing the executables built for the targets
+// listed in archs, mapped to the architecture names of the package manager.
+// The artifacts produced by the packagers are not referenced
+func (r ReleaseInfo) manifestData(in PluginInput, archs map[string]string) (ManifestData, error) {
+	data, err := r.releaseData(in.WorkDir)
+	if err != nil {
+		return data, err
+	}
+	if data.ID == "" || data.Version == "" || data.URL == "" {
+		return data, fmt.Errorf("The package identifier, version and release URL are required, see FYNE_CROSS_APP_ID, FYNE_CROSS_APP_VERSION and FYNE_CROSS_RELEASE_URL")
+	}
+
+	urlTmpl, err := template.New("url").Parse(data.URL)
+	if err != nil {
+		return data, fmt.Errorf("Invalid release URL template %s", err)
+	}
+	for _, a := range in.Artifacts {
+		arch, ok := archs[a.Target]
+		if !ok || a.Package == "" {
+			continue
+		}
+		installer := ManifestInstaller{
+			Target: a.Target,
+			Arch:   arch,
+			Name:   assetName(a.File),
+			SHA256: a.SHA256,
+		}
+		var url bytes.Buffer
+		err = urlTmpl.Execute(&url, struct{ Version, Tag, Name string }{data.Version, data.Tag, installer.Name})
+		if err != nil {
+			return data, fmt.Errorf("Cannot execute the release URL template %s", err)
+		}
+		installer.URL = url.String()
+		data.Installers = append(data.Installers, installer)
+	}
+	if len(data.Installers) == 0 {
+		return data, fmt.Errorf("No artifacts of the supported targets")
+	}
+	return data, nil
+}
+
+// readLocales returns the localized metadata of the locales file sorted by locale
+func readLocales(file string) ([]ManifestLocale, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot read the locales file %s", err)
+	}
+	m := map[string]ManifestLocale{}
+	err = json.Unmarshal(b, &m)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid locales file %s", err)
+	}
+
+	locales := []ManifestLocale{}
+	for locale, l := range m {
+		if !localeRegexp.MatchString(locale) {
+			return nil, fmt.Errorf("Invalid locale %q, expected a language tag i.e. de-DE", locale)
+		}
+		if l.Name == "" && l.Description == "" {
+			return nil, fmt.Errorf("The name or the description is required for the locale %q", locale)
+		}
+		l.Locale = locale
+		locales = append(locales, l)
+	}
+	sort.Slice(locales, func(i, j int) bool {
+		return locales[i].Locale < locales[j].Locale
+	})
+	return locales, nil
+}
+
+// writeManifest executes the named template of the packager, the builtin one unless a custom
+// template is found, see ReleaseInfo.TemplateDir. The manifest is written to file, relative
+// to the packager folder of the build folder
+func (d ManifestData) writeManifest(in PluginInput, packager string, name string, builtin string, file string) (Artifact, error) {
+	text := builtin
+	if d.TemplateDir != "" {
+		b, err := ioutil.ReadFile(filepath.Join(d.TemplateDir, packager, name+".tmpl"))
+		switch {
+		case err == nil:
+			text = string(b)
+		case !os.IsNotExist(err):
+			return Artifact{}, fmt.Errorf("Cannot read the %s template %s", name, err)
+		}
+	}
+
+	tmpl, err := template.New(name).Funcs(manifestFuncs).Parse(text)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("Invalid %s template %s", name, err)
+	}
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, d)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("Cannot execute the %s template %s", name, err)
+	}
+
+	rel := path.Join(packager, file)
+	dest := filepath.Join(in.BuildDir, filepath.FromSlash(rel))
+	err = os.MkdirAll(filepath.Dir(dest), 0755)
+	if err != nil {
+		return Artifact{}, err
+	}
+	err = ioutil.WriteFile(dest, buf.Bytes(), 0644)
+	if err != nil {
+		return Artifact{}, err
+	}
+	fmt.Fprintf(in.Stdout, "Generated %s\n", rel)
+	return Artifact{Target: d.Installers[0].Target, File: rel}, nil
+}
@@ -0,0 +1,503 @@
+package build
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// testReleaseInfo is the release metadata used to test the packagers
+var testReleaseInfo = ReleaseInfo{
+	ID:          "FyneIO.Example",
+	Name:        "Fyne Example",
+	Version:     "1.2.0",
+	Publisher:   "Fyne.io",
+	Description: "The Fyne example application",
+	Homepage:    "https://fyne.io",
+	License:     "BSD-3-Clause",
+	URL:         "https://example.com/{{.Version}}/{{.Name}}",
+}
+
+// testPackagerArtifacts are the artifacts used to test the packagers
+var testPackagerArtifacts = []Artifact{
+	{Target: "linux/amd64", Package: ".", File: "example-linux-amd64", SHA256: "11"},
+	{Target: "windows/amd64", Package: ".", File: "windows-amd64/example-windows-amd64.exe", SHA256: "22"},
+	{Target: "windows/386", Package: ".", File: "example-windows-386.exe", SHA256: "33"},
+	{Target: "darwin/amd64", Package: ".", File: "example-darwin-amd64", SHA256: "44"},
+	{Target: "windows/amd64", File: "example-windows-amd64.msi", SHA256: "55"},
+}
+
+func TestReleaseInfo_manifestData(t *testing.T) {
+	got, err := testReleaseInfo.manifestData(PluginInput{Artifacts: testPackagerArtifacts}, chocoArchs)
+	if err != nil {
+		t.Fatalf("ReleaseInfo.manifestData() error = %v", err)
+	}
+	want := []ManifestInstaller{
+		{Target: "windows/amd64", Arch: "x64", Name: "example-windows-amd64.exe", URL: "https://example.com/1.2.0/example-windows-amd64.exe", SHA256: "22"},
+		{Target: "windows/386", Arch: "x86", Name: "example-windows-386.exe", URL: "https://example.com/1.2.0/example-windows-386.exe", SHA256: "33"},
+	}
+	if !reflect.DeepEqual(got.Installers, want) {
+		t.Errorf("ReleaseInfo.manifestData() installers = %v, want %v", got.Installers, want)
+	}
+	if got.Command != "example" {
+		t.Errorf("ReleaseInfo.manifestData() command = %v, want %v", got.Command, "example")
+	}
+
+	_, err = testReleaseInfo.manifestData(PluginInput{Artifacts: testPackagerArtifacts[:1]}, chocoArchs)
+	if err == nil {
+		t.Errorf("ReleaseInfo.manifestData() expected error for no supported artifacts")
+	}
+}
+
+func TestManifestData_writeManifest_customTemplate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fyne-cross-packager")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	err = os.MkdirAll(filepath.Join(dir, "templates", "homebrew"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, "templates", "homebrew", "cask.rb.tmpl"), []byte(`{{.ID}} {{.Version}}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := testReleaseInfo
+	info.TemplateDir = filepath.Join(dir, "templates")
+	p := &HomebrewPackager{info}
+	in := PluginInput{BuildDir: filepath.Join(dir, "build"), Artifacts: testPackagerArtifacts, Stdout: ioutil.Discard}
+	_, err = p.Package(context.Background(), in)
+	if err != nil {
+		t.Fatalf("Package() error = %v", err)
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "build", "homebrew", "Casks", "fyneio-example.rb"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "FyneIO.Example 1.2.0"; string(b) != want {
+		t.Errorf("Package() = %s, want %s", b, want)
+	}
+}
+
+func TestPackagers(t *testing.T) {
+	tests := []struct {
+		name     string
+		packager Packager
+		want     map[string]string
+	}{
+		{
+			name:     "winget",
+			packager: &WingetPackager{testReleaseInfo},
+			want: map[string]string{
+				"winget/manifests/f/FyneIO/Example/1.2.0/FyneIO.Example.yaml": `# Generated by fyne-cross
+PackageIdentifier: FyneIO.Example
+PackageVersion: 1.2.0
+DefaultLocale: en-US
+ManifestType: version
+ManifestVersion: 1.4.0
+`,
+				"winget/manifests/f/FyneIO/Example/1.2.0/FyneIO.Example.installer.yaml": `# Generated by fyne-cross
+PackageIdentifier: FyneIO.Example
+PackageVersion: 1.2.0
+InstallerType: portable
+Commands:
+  - example
+Installers:
+  - Architecture: x64
+    InstallerUrl: https://example.com/1.2.0/example-windows-amd64.exe
+    InstallerSha256: 22
+  - Architecture: x86
+    InstallerUrl: https://example.com/1.2.0/example-windows-386.exe
+    InstallerSha256: 33
+ManifestType: installer
+ManifestVersion: 1.4.0
+`,
+				"winget/manifests/f/FyneIO/Example/1.2.0/FyneIO.Example.locale.en-US.yaml": `# Generated by fyne-cross
+PackageIdentifier: FyneIO.Example
+PackageVersion: 1.2.0
+PackageLocale: en-US
+Publisher: "Fyne.io"
+PackageName: "Fyne Example"
+License: "BSD-3-Clause"
+ShortDescription: "The Fyne example application"
+PackageUrl: https://fyne.io
+ManifestType: defaultLocale
+ManifestVersion: 1.4.0
+`,
+			},
+		},
+		{
+			name:     "choco",
+			packager: &ChocoPackager{testReleaseInfo},
+			want: map[string]string{
+				"choco/fyneio.example/fyneio.example.nuspec": `<?xml version="1.0" encoding="utf-8"?>
+<!-- Generated by fyne-cross -->
+<package xmlns="http://schemas.microsoft.com/packaging/2015/06/nuspec.xsd">
+  <metadata>
+    <id>fyneio.example</id>
+    <version>1.2.0</version>
+    <title>Fyne Example</title>
+    <authors>Fyne.io</authors>
+    <projectUrl>https://fyne.io</projectUrl>
+    <summary>The Fyne example application</summary>
+    <description>The Fyne example application</description>
+  </metadata>
+  <files>
+    <file src="tools\**" target="tools" />
+  </files>
+</package>
+`,
+				"choco/fyneio.example/tools/chocolateyinstall.ps1": `# Generated by fyne-cross
+$ErrorActionPreference = 'Stop'
+$toolsDir = "$(Split-Path -parent $MyInvocation.MyCommand.Definition)"
+
+$packageArgs = @{
+  packageName    = $env:ChocolateyPackageName
+  fileFullPath   = Join-Path $toolsDir 'example.exe'
+  url64bit       = 'https://example.com/1.2.0/example-windows-amd64.exe'
+  checksum64     = '22'
+  checksumType64 = 'sha256'
+  url            = 'https://example.com/1.2.0/example-windows-386.exe'
+  checksum       = '33'
+  checksumType   = 'sha256'
+}
+
+Get-ChocolateyWebFile @packageArgs
+`,
+			},
+		},
+		{
+			name:     "homebrew",
+			packager: &HomebrewPackager{testReleaseInfo},
+			want: map[string]string{
+				"homebrew/Casks/fyneio-example.rb": `# Generated by fyne-cross
+cask "fyneio-example" do
+  version "1.2.0"
+  sha256 "44"
+
+  url "https://example.com/1.2.0/example-darwin-amd64"
+
+  name "Fyne Example"
+  desc "The Fyne example application"
+  homepage "https://fyne.io"
+
+  binary "example-darwin-amd64", target: "example"
+end
+`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "fyne-cross-packager")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			in := PluginInput{BuildDir: dir, Artifacts: testPackagerArtifacts, Stdout: ioutil.Discard}
+			artifacts, err := tt.packager.Package(context.Background(), in)
+			if err != nil {
+				t.Fatalf("Package() error = %v", err)
+			}
+			if len(artifacts) != len(tt.want) {
+				t.Errorf("Package() = %v, want %d artifacts", artifacts, len(tt.want))
+			}
+			for _, a := range artifacts {
+				want, ok := tt.want[a.File]
+				if !ok {
+					t.Errorf("Package() unexpected artifact %s", a.File)
+					continue
+				}
+				b, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(a.File)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(b) != want {
+					t.Errorf("Package() %s = %s, want %s", a.File, b, want)
+				}
+			}
+		})
+	}
+}
+
+func Test_readLocales(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fyne-cross-packager")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name    string
+		content string
+		want    []ManifestLocale
+		wantErr bool
+	}{
+		{
+			name:    "sorted by locale",
+			content: `{"it-IT": {"name": "Esempio Fyne"}, "de-DE": {"name": "Fyne Beispiel", "description": "Die Fyne Beispielanwendung"}}`,
+			want: []ManifestLocale{
+				{Locale: "de-DE", Name: "Fyne Beispiel", Description: "Die Fyne Beispielanwendung"},
+				{Locale: "it-IT", Name: "Esempio Fyne"},
+			},
+		},
+		{
+			name:    "invalid locale",
+			content: `{"de_DE": {"name": "Fyne Beispiel"}}`,
+			wantErr: true,
+		},
+		{
+			name:    "empty metadata",
+			content: `{"de-DE": {}}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid json",
+			content: `["de-DE"]`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(dir, "locales.json")
+			err := ioutil.WriteFile(file, []byte(tt.content), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := readLocales(file)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("readLocales() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readLocales() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPackagers_locales(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fyne-cross-packager")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	info := testReleaseInfo
+	info.LocalesFile = filepath.Join(dir, "locales.json")
+	err = ioutil.WriteFile(info.LocalesFile, []byte(`{"de-DE": {"name": "Fyne Beispiel", "description": "Die Fyne Beispielanwendung"}}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		packager Packager
+		file     string
+		want     string
+	}{
+		{
+			name:     "winget",
+			packager: &WingetPackager{info},
+			file:     "winget/manifests/f/FyneIO/Example/1.2.0/FyneIO.Example.locale.de-DE.yaml",
+			want: `# Generated by fyne-cross
+PackageIdentifier: FyneIO.Example
+PackageVersion: 1.2.0
+PackageLocale: de-DE
+PackageName: "Fyne Beispiel"
+ShortDescription: "Die Fyne Beispielanwendung"
+ManifestType: locale
+ManifestVersion: 1.4.0
+`,
+		},
+		{
+			name:     "homebrew",
+			packager: &HomebrewPackager{info},
+			file:     "homebrew/Casks/fyneio-example.rb",
+			want: `# Generated by fyne-cross
+cask "fyneio-example" do
+  version "1.2.0"
+  sha256 "44"
+
+  url "https://example.com/1.2.0/example-darwin-amd64"
+
+  name "Fyne Example"
+  name "Fyne Beispiel"
+  desc "The Fyne example application"
+  homepage "https://fyne.io"
+
+  binary "example-darwin-amd64", target: "example"
+end
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buildDir := filepath.Join(dir, tt.name)
+			in := PluginInput{BuildDir: buildDir, Artifacts: testPackagerArtifacts, Stdout: ioutil.Discard}
+			_, err := tt.packager.Package(context.Background(), in)
+			if err != nil {
+				t.Fatalf("Package() error = %v", err)
+			}
+			b, err := ioutil.ReadFile(filepath.Join(buildDir, filepath.FromSlash(tt.file)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.want {
+				t.Errorf("Package() %s = %s, want %s", tt.file, b, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalesPackager_Package(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fyne-cross-packager")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "locales.json"), []byte(`{"de-DE": {"name": "Fyne Beispiel", "description": "Die Fyne Beispielanwendung"}}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.MkdirAll(filepath.Join(dir, "translations"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, "translations", "de.json"), []byte(`{"Hello": "Hallo"}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the locales file and the translations folder are relative to the project folder
+	info := testReleaseInfo
+	info.LocalesFile = "locales.json"
+	info.TranslationsDir = "translations"
+	p := &LocalesPackager{info}
+	buildDir := filepath.Join(dir, "build")
+	in := PluginInput{BuildDir: buildDir, WorkDir: dir, Artifacts: testPackagerArtifacts, Stdout: ioutil.Discard}
+	artifacts, err := p.Package(context.Background(), in)
+	if err != nil {
+		t.Fatalf("Package() error = %v", err)
+	}
+
+	want := map[string]string{
+		"locales/linux/example.desktop": `# Generated by fyne-cross
+[Desktop Entry]
+Type=Application
+Name=Fyne Example
+Name[de_DE]=Fyne Beispiel
+Comment=The Fyne example application
+Comment[de_DE]=Die Fyne Beispielanwendung
+Exec=example
+Icon=example
+`,
+		"locales/linux/translations/de.json": `{"Hello": "Hallo"}`,
+		"locales/windows/en-US.wxl": `<?xml version="1.0" encoding="utf-8"?>
+<!-- Generated by fyne-cross -->
+<WixLocalization Culture="en-US" xmlns="http://schemas.microsoft.com/wix/2006/localization">
+  <String Id="ProductName">Fyne Example</String>
+  <String Id="ProductDescription">The Fyne example application</String>
+</WixLocalization>
+`,
+		"locales/windows/de-DE.wxl": `<?xml version="1.0" encoding="utf-8"?>
+<!-- Generated by fyne-cross -->
+<WixLocalization Culture="de-DE" xmlns="http://schemas.microsoft.com/wix/2006/localization">
+  <String Id="ProductName">Fyne Beispiel</String>
+  <String Id="ProductDescription">Die Fyne Beispielanwendung</String>
+</WixLocalization>
+`,
+		"locales/windows/translations/de.json": `{"Hello": "Hallo"}`,
+		"locales/darwin/Info.plist": `<?xml version="1.0" encoding="UTF-8"?>
+<!-- Generated by fyne-cross -->
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>CFBundleDevelopmentRegion</key>
+	<string>en</string>
+	<key>CFBundleDisplayName</key>
+	<string>Fyne Example</string>
+	<key>CFBundleExecutable</key>
+	<string>example</string>
+	<key>CFBundleIdentifier</key>
+	<string>FyneIO.Example</string>
+	<key>CFBundleName</key>
+	<string>Fyne Example</string>
+	<key>CFBundlePackageType</key>
+	<string>APPL</string>
+	<key>CFBundleShortVersionString</key>
+	<string>1.2.0</string>
+	<key>CFBundleLocalizations</key>
+	<array>
+		<string>en</string>
+		<string>de-DE</string>
+	</array>
+</dict>
+</plist>
+`,
+		"locales/darwin/Resources/de-DE.lproj/InfoPlist.strings": `/* Generated by fyne-cross */
+"CFBundleName" = "Fyne Beispiel";
+"CFBundleDisplayName" = "Fyne Beispiel";
+`,
+		"locales/darwin/Resources/translations/de.json": `{"Hello": "Hallo"}`,
+	}
+	if len(artifacts) != len(want) {
+		t.Errorf("Package() = %v, want %d artifacts", artifacts, len(want))
+	}
+	for _, a := range artifacts {
+		w, ok := want[a.File]
+		if !ok {
+			t.Errorf("Package() unexpected artifact %s", a.File)
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(buildDir, filepath.FromSlash(a.File)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != w {
+			t.Errorf("Package() %s = %s, want %s", a.File, b, w)
+		}
+	}
+}
+
+func TestPackagers_defaultLocale(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fyne-cross-packager")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	info := testReleaseInfo
+	info.LocalesFile = filepath.Join(dir, "locales.json")
+	err = ioutil.WriteFile(info.LocalesFile, []byte(`{"en-US": {"name": "Fyne Example"}}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	packagers := map[string]Packager{
+		"winget":  &WingetPackager{info},
+		"locales": &LocalesPackager{info},
+	}
+	for name, p := range packagers {
+		t.Run(name, func(t *testing.T) {
+			buildDir := filepath.Join(dir, name)
+			in := PluginInput{BuildDir: buildDir, Artifacts: testPackagerArtifacts, Stdout: ioutil.Discard}
+			_, err := p.Package(context.Background(), in)
+			if err == nil {
+				t.Errorf("Package() expected error for the en-US locale")
+			}
+			if _, err := os.Stat(buildDir); !os.IsNotExist(err) {
+				t.Errorf("Package() wrote files before failing: %v", err)
+			}
+		})
+	}
+}
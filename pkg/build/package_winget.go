@@ -0,0 +1,133 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+)
+
+func init() {
+	RegisterPackager("winget", &WingetPackager{})
+}
+
+// wingetArchs maps the targets to the winget installer architectures
+var wingetArchs = map[string]string{
+	"windows/386":   "x86",
+	"windows/amd64": "x64",
+	"windows/arm64": "arm64",
+}
+
+// wingetVersionTemplate is the template of the winget version manifest
+const wingetVersionTemplate = `# Generated by fyne-cross
+PackageIdentifier: {{.ID}}
+PackageVersion: {{.Version}}
+DefaultLocale: en-US
+ManifestType: version
+ManifestVersion: 1.4.0
+`
+
+// wingetInstallerTemplate is the template of the winget installer manifest
+const wingetInstallerTemplate = `# Generated by fyne-cross
+PackageIdentifier: {{.ID}}
+PackageVersion: {{.Version}}
+InstallerType: portable
+Commands:
+  - {{.Command}}
+Installers:
+{{- range .Installers}}
+  - Architecture: {{.Arch}}
+    InstallerUrl: {{.URL}}
+    InstallerSha256: {{.SHA256}}
+{{- end}}
+ManifestType: installer
+ManifestVersion: 1.4.0
+`
+
+// wingetLocaleTemplate is the template of the winget default locale manifest
+const wingetLocaleTemplate = `# Generated by fyne-cross
+PackageIdentifier: {{.ID}}
+PackageVersion: {{.Version}}
+PackageLocale: en-US
+Publisher: {{printf "%q" .Publisher}}
+PackageName: {{printf "%q" .Name}}
+License: {{printf "%q" .License}}
+ShortDescription: {{printf "%q" .Description}}
+{{- if .Homepage}}
+PackageUrl: {{.Homepage}}
+{{- end}}
+ManifestType: defaultLocale
+ManifestVersion: 1.4.0
+`
+
+// wingetAdditionalLocaleTemplate is the template of the winget manifest of a locale
+// of the locales file, see ReleaseInfo.LocalesFile
+const wingetAdditionalLocaleTemplate = `# Generated by fyne-cross
+PackageIdentifier: {{.ID}}
+PackageVersion: {{.Version}}
+PackageLocale: {{.Locale.Locale}}
+{{- if .Locale.Name}}
+PackageName: {{printf "%q" .Locale.Name}}
+{{- end}}
+{{- if .Locale.Description}}
+ShortDescription: {{printf "%q" .Locale.Description}}
+{{- end}}
+ManifestType: locale
+ManifestVersion: 1.4.0
+`
+
+// WingetPackager generates the winget manifests of the windows executables as portable installers.
+// The manifests are written into the build/winget folder following the layout of the
+// winget-pkgs repository, i.e. manifests/f/FyneIO/Example/1.0.0.
+// The package identifier, publisher, license and description are required
+type WingetPackager struct {
+	ReleaseInfo
+}
+
+// Package generates the version, installer and default locale manifests,
+// plus a locale manifest for each locale of the locales file
+func (p *WingetPackager) Package(ctx context.Context, in PluginInput) ([]Artifact, error) {
+	data, err := p.manifestData(in, wingetArchs)
+	if err != nil {
+		return nil, err
+	}
+	if data.Publisher == "" || data.License == "" || data.Description == "" {
+		return nil, fmt.Errorf("The publisher, license and description are required by winget, see FYNE_CROSS_PUBLISHER, FYNE_CROSS_LICENSE and FYNE_CROSS_DESCRIPTION")
+	}
+	for _, l := range data.Locales {
+		if l.Locale == "en-US" {
+			return nil, fmt.Errorf("The en-US metadata is the winget default locale, see FYNE_CROSS_APP_NAME and FYNE_CROSS_DESCRIPTION")
+		}
+	}
+
+	dir := path.Join("manifests", strings.ToLower(data.ID[:1]), strings.Replace(data.ID, ".", "/", -1), data.Version)
+	manifests := []struct {
+		name    string
+		builtin string
+		file    string
+	}{
+		{"version.yaml", wingetVersionTemplate, data.ID + ".yaml"},
+		{"installer.yaml", wingetInstallerTemplate, data.ID + ".installer.yaml"},
+		{"locale.yaml", wingetLocaleTemplate, data.ID + ".locale.en-US.yaml"},
+	}
+
+	artifacts := []Artifact{}
+	for _, m := range manifests {
+		a, err := data.writeManifest(in, "winget", m.name, m.builtin, path.Join(dir, m.file))
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, a)
+	}
+
+	for _, l := range data.Locales {
+		ld := data
+		ld.Locale = l
+		a, err := ld.writeManifest(in, "winget", "additional-locale.yaml", wingetAdditionalLocaleTemplate, path.Join(dir, data.ID+".locale."+l.Locale+".yaml"))
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, a)
+	}
+	return artifacts, nil
+}
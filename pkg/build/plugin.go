@@ -0,0 +1,201 @@
+package build
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// pluginPrefix is the prefix of the executables implementing the exec-based plugins.
+// Example: the packager named snap is implemented by the fyne-cross-snap executable
+const pluginPrefix = "fyne-cross-"
+
+// PluginInput represents the input passed to the packagers and publishers
+type PluginInput struct {
+	// BuildDir is the absolute path of the build output folder
+	BuildDir string `json:"build_dir"`
+	// WorkDir is the absolute path of the project folder, the relative paths of the plugin
+	// settings are resolved against
+	WorkDir string `json:"work_dir"`
+	// Artifacts are the artifacts of the build. Files are relative to BuildDir
+	Artifacts []Artifact `json:"artifacts"`
+	// Stdout is the writer for the plugin messages
+	Stdout io.Writer `json:"-"`
+	// Stderr is the writer for the plugin errors
+	Stderr io.Writer `json:"-"`
+}
+
+// Packager packages the artifacts of a build, i.e. into an installer or a package manager manifest
+type Packager interface {
+	// Package writes the packages into the build folder and returns them as artifacts.
+	// Only the Target and File fields are required, size and checksum are computed by the builder
+	Package(ctx context.Context, in PluginInput) ([]Artifact, error)
+}
+
+// Publisher publishes the artifacts of a build, i.e. to a release page or a storage
+type Publisher interface {
+	// Publish publishes the artifacts
+	Publish(ctx context.Context, in PluginInput) error
+}
+
+var (
+	pluginsMu  sync.Mutex
+	packagers  = map[string]Packager{}
+	publishers = map[string]Publisher{}
+)
+
+// RegisterPackager makes the packager available by name.
+// It panics if a packager with the same name is already registered
+func RegisterPackager(name string, p Packager) {
+	pluginsMu.Lock()
+	defer pluginsMu.Unlock()
+	if _, ok := packagers[name]; ok {
+		panic("build: RegisterPackager called twice for packager " + name)
+	}
+	packagers[name] = p
+}
+
+// RegisterPublisher makes the publisher available by name.
+// It panics if a publisher with the same name is already registered
+func RegisterPublisher(name string, p Publisher) {
+	pluginsMu.Lock()
+	defer pluginsMu.Unlock()
+	if _, ok := publishers[name]; ok {
+		panic("build: RegisterPublisher called twice for publisher " + name)
+	}
+	publishers[name] = p
+}
+
+// LookupPackager returns the packager registered with name.
+// When none is registered the fyne-cross-<name> executable found in PATH is used, see execPlugin
+func LookupPackager(name string) (Packager, error) {
+	pluginsMu.Lock()
+	p, ok := packagers[name]
+	pluginsMu.Unlock()
+	if ok {
+		return p, nil
+	}
+
+	path, err := exec.LookPath(pluginPrefix + name)
+	if err != nil {
+		return nil, fmt.Errorf("Unknown packager %q", name)
+	}
+	return &execPlugin{path: path}, nil
+}
+
+// LookupPublisher returns the publisher registered with name.
+// When none is registered the fyne-cross-<name> executable found in PATH is used, see execPlugin.
+// A name containing "://" is an upload destination, see NewUploadPublisher
+func LookupPublisher(name string) (Publisher, error) {
+	if strings.Contains(name, "://") {
+		return NewUploadPublisher(name)
+	}
+
+	pluginsMu.Lock()
+	p, ok := publishers[name]
+	pluginsMu.Unlock()
+	if ok {
+		return p, nil
+	}
+
+	path, err := exec.LookPath(pluginPrefix + name)
+	if err != nil {
+		return nil, fmt.Errorf("Unknown publisher %q", name)
+	}
+	return &execPlugin{path: path}, nil
+}
+
+// execPlugin is a packager and publisher implemented by an external executable.
+// The executable is run as "fyne-cross-<name> package" or "fyne-cross-<name> publish"
+// with the plugin input encoded as JSON on stdin.
+// Packagers write the packaged artifacts encoded as JSON on stdout, messages go to stderr
+type execPlugin struct {
+	path string
+}
+
+// Package runs the executable with the package action
+func (p *execPlugin) Package(ctx context.Context, in PluginInput) ([]Artifact, error) {
+	var stdout bytes.Buffer
+	err := p.run(ctx, "package", in, &stdout)
+	if err != nil {
+		return nil, err
+	}
+
+	artifacts := []Artifact{}
+	err = json.Unmarshal(stdout.Bytes(), &artifacts)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot decode the artifacts returned by %s %s", filepath.Base(p.path), err)
+	}
+	return artifacts, nil
+}
+
+// Publish runs the executable with the publish action
+func (p *execPlugin) Publish(ctx context.Context, in PluginInput) error {
+	return p.run(ctx, "publish", in, in.Stdout)
+}
+
+// run runs the executable for action passing the plugin input on stdin
+func (p *execPlugin) run(ctx context.Context, action string, in PluginInput, stdout io.Writer) error {
+	b, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, p.path, action)
+	cmd.Stdin = bytes.NewReader(b)
+	cmd.Stdout = stdout
+	cmd.Stderr = in.Stderr
+	return cmd.Run()
+}
+
+// pluginInput returns the plugin input for the artifacts
+func (d *Builder) pluginInput(artifacts []Artifact) PluginInput {
+	return PluginInput{
+		BuildDir:  filepath.Join(d.workDir, "build"),
+		WorkDir:   d.workDir,
+		Artifacts: artifacts,
+		Stdout:    d.stdout,
+		Stderr:    d.stderr,
+	}
+}
+
+// runPackager runs the packager on the artifacts and returns the described packages
+func (d *Builder) runPackager(name string, artifacts []Artifact) ([]Artifact, error) {
+	p, err := LookupPackager(name)
+	if err != nil {
+		return nil, err
+	}
+
+	in := d.pluginInput(artifacts)
+	packaged, err := p.Package(d.ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	described := []Artifact{}
+	for _, a := range packaged {
+		da, err := newArtifact(a.Target, filepath.Join(in.BuildDir, filepath.FromSlash(a.File)))
+		if err != nil {
+			return nil, fmt.Errorf("Cannot describe the package %s: %s", a.File, err)
+		}
+		// keep the subdir, if any
+		da.File = a.File
+		described = append(described, da)
+	}
+	return described, nil
+}
+
+// runPublisher runs the publisher on the artifacts
+func (d *Builder) runPublisher(name string, artifacts []Artifact) error {
+	p, err := LookupPublisher(name)
+	if err != nil {
+		return err
+	}
+	return p.Publish(d.ctx, d.pluginInput(artifacts))
+}
@@ -0,0 +1,71 @@
+package build
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+type fakePackager struct{}
+
+func (p fakePackager) Package(ctx context.Context, in PluginInput) ([]Artifact, error) {
+	return nil, nil
+}
+
+func TestLookupPackager(t *testing.T) {
+	RegisterPackager("fake", fakePackager{})
+	defer delete(packagers, "fake")
+
+	got, err := LookupPackager("fake")
+	if err != nil {
+		t.Fatalf("LookupPackager() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, fakePackager{}) {
+		t.Errorf("LookupPackager() = %v, want %v", got, fakePackager{})
+	}
+
+	_, err = LookupPackager("fyne-cross-test-missing")
+	if err == nil {
+		t.Errorf("LookupPackager() expected error for a missing packager")
+	}
+}
+
+func Test_execPlugin_Package(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts are not supported")
+	}
+
+	dir, err := ioutil.TempDir("", "fyne-cross-plugin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	script := "#!/bin/sh\ntest \"$1\" = package || exit 1\ncat > /dev/null\necho '[{\"target\":\"linux/amd64\",\"file\":\"app.tar.gz\"}]'\n"
+	err = ioutil.WriteFile(filepath.Join(dir, "fyne-cross-fake-exec"), []byte(script), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := os.Getenv("PATH")
+	os.Setenv("PATH", dir+string(os.PathListSeparator)+path)
+	defer os.Setenv("PATH", path)
+
+	p, err := LookupPackager("fake-exec")
+	if err != nil {
+		t.Fatalf("LookupPackager() error = %v", err)
+	}
+
+	got, err := p.Package(context.Background(), PluginInput{BuildDir: dir, Stderr: ioutil.Discard})
+	if err != nil {
+		t.Fatalf("execPlugin.Package() error = %v", err)
+	}
+	want := []Artifact{{Target: "linux/amd64", File: "app.tar.gz"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("execPlugin.Package() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,248 @@
+package build
+
+import (
+	"bufio"
+	"fmt"
+	gobuild "go/build"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// preflight validates the package to build before launching docker, so that
+// errors like a typo into the package path are reported early and precisely
+func (d *Builder) preflight() error {
+	if !d.gomod && d.importPath == "" {
+		fmt.Fprintln(d.stdout, "Warning: no go.mod found and the package root directory is outside the GOPATH, imports of the project packages cannot be resolved")
+	}
+
+	dir, err := d.packageDir()
+	if err != nil {
+		return err
+	}
+	if dir == "" {
+		// package outside the project, it will be fetched by go get
+		return nil
+	}
+	return checkMainPackage(dir)
+}
+
+// checkTargetConstraints returns an error if the build constraints of the package exclude target,
+// i.e. a package using linux only syscalls built for windows. Only the files of the package are
+// evaluated, packages outside the project are not checked
+func (d *Builder) checkTargetConstraints(target string) error {
+	dir, err := d.packageDir()
+	if err != nil || dir == "" {
+		return nil
+	}
+
+	parts := strings.Split(target, "/")
+	ctx := gobuild.Default
+	ctx.GOOS, ctx.GOARCH = parts[0], parts[1]
+	ctx.CgoEnabled = true
+
+	if strings.HasSuffix(dir, ".go") {
+		match, err := ctx.MatchFile(filepath.Dir(dir), filepath.Base(dir))
+		if err == nil && !match {
+			return fmt.Errorf("the build constraints exclude %s", d.pkg)
+		}
+		return nil
+	}
+
+	pkg, err := ctx.ImportDir(dir, 0)
+	if _, ok := err.(*gobuild.NoGoError); ok {
+		return fmt.Errorf("the build constraints exclude all the go files of %s", d.pkg)
+	}
+	if err != nil {
+		// reported by go build
+		return nil
+	}
+	if pkg.Name != "main" {
+		return fmt.Errorf("the build constraints exclude the main package files of %s", d.pkg)
+	}
+	return nil
+}
+
+// packageDirName returns the name of the package directory, the work dir one for the current package
+func packageDirName(workDir string, pkg string) string {
+	if pkg == "." {
+		return filepath.Base(workDir)
+	}
+	parts := strings.Split(pkg, "/")
+	return parts[len(parts)-1]
+}
+
+// checkOutputNames returns an error if more packages have the same directory name,
+// since their outputs would overwrite each other into the build folder
+func checkOutputNames(workDir string, pkgs []string) error {
+	seen := map[string]string{}
+	for _, pkg := range pkgs {
+		name := packageDirName(workDir, pkg)
+		if other, ok := seen[name]; ok {
+			return fmt.Errorf("The packages %s and %s have the same output name %q, build them in separate runs using the output option", other, pkg, name)
+		}
+		seen[name] = pkg
+	}
+	return nil
+}
+
+// expandPackages expands the relative patterns ending with /... into the main packages
+// found below the pattern directory. The other packages are returned as is
+func expandPackages(workDir string, pkgs []string) ([]string, error) {
+	expanded := []string{}
+	for _, pkg := range pkgs {
+		if !strings.HasSuffix(pkg, "/...") {
+			expanded = append(expanded, pkg)
+			continue
+		}
+		if !strings.HasPrefix(pkg, "./") {
+			return nil, fmt.Errorf("Unsupported package pattern %q, only relative patterns are supported, i.e. ./cmd/...", pkg)
+		}
+
+		root := filepath.Join(workDir, filepath.FromSlash(strings.TrimSuffix(pkg, "/...")))
+		found := []string{}
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() {
+				return nil
+			}
+			// skip the directories ignored by the go tool and the build output folder
+			name := info.Name()
+			if path != root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor") {
+				return filepath.SkipDir
+			}
+			if path == filepath.Join(workDir, "build") {
+				return filepath.SkipDir
+			}
+
+			if checkMainPackage(path) != nil {
+				return nil
+			}
+			rel, err := filepath.Rel(workDir, path)
+			if err != nil {
+				return err
+			}
+			if rel == "." {
+				found = append(found, ".")
+				return nil
+			}
+			found = append(found, "./"+filepath.ToSlash(rel))
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Cannot expand the package pattern %s %s", pkg, err)
+		}
+		if len(found) == 0 {
+			return nil, fmt.Errorf("Cannot find main packages matching %s", pkg)
+		}
+		expanded = append(expanded, found...)
+	}
+	return expanded, nil
+}
+
+// packageDir returns the host directory of the package to build.
+// An empty string is returned if the package is not part of the project
+func (d *Builder) packageDir() (string, error) {
+	if d.pkg == "." || strings.HasPrefix(d.pkg, "./") || strings.HasPrefix(d.pkg, "../") {
+		return filepath.Join(d.workDir, filepath.FromSlash(d.pkg)), nil
+	}
+
+	if filepath.IsAbs(d.pkg) {
+		return d.pkg, nil
+	}
+
+	root := d.importPath
+	if d.gomod {
+		var err error
+		root, err = modulePath(filepath.Join(d.workDir, "go.mod"))
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if root != "" && (d.pkg == root || strings.HasPrefix(d.pkg, root+"/")) {
+		rel := strings.TrimPrefix(strings.TrimPrefix(d.pkg, root), "/")
+		return filepath.Join(d.workDir, filepath.FromSlash(rel)), nil
+	}
+	return "", nil
+}
+
+// modulePath returns the module path declared into the go.mod file
+func modulePath(gomod string) (string, error) {
+	f, err := os.Open(gomod)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "module" {
+			continue
+		}
+		path := fields[1]
+		if unquoted, err := strconv.Unquote(path); err == nil {
+			path = unquoted
+		}
+		return path, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("Cannot find the module path in %s", gomod)
+}
+
+// checkMainPackage checks that dir exists and contains a main package
+func checkMainPackage(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("Cannot find the package directory %s", dir)
+	}
+	if !info.IsDir() {
+		if strings.HasSuffix(dir, ".go") {
+			return checkMainPackageFiles([]string{dir})
+		}
+		return fmt.Errorf("The package %s is not a directory", dir)
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	files := []string{}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		files = append(files, filepath.Join(dir, name))
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("Cannot find go files in the package directory %s", dir)
+	}
+	return checkMainPackageFiles(files)
+}
+
+// checkMainPackageFiles checks that at least one of the go files declares the main package
+func checkMainPackageFiles(files []string) error {
+	fset := token.NewFileSet()
+	found := ""
+	for _, file := range files {
+		f, err := parser.ParseFile(fset, file, nil, parser.PackageClauseOnly)
+		if err != nil {
+			return fmt.Errorf("Cannot parse %s", err)
+		}
+		if f.Name.Name == "main" {
+			return nil
+		}
+		found = f.Name.Name
+	}
+	return fmt.Errorf("The package %s is not a main package, found package %s", filepath.Dir(files[0]), found)
+}
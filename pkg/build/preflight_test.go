@@ -0,0 +1,213 @@
+package build
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBuilder_preflight(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fyne-cross")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	write := func(name string, content string) {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		err := ioutil.WriteFile(path, []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("go.mod", "module github.com/fyne-io/fyne-example\n\ngo 1.12\n")
+	write("main.go", "package main\n\nfunc main() {}\n")
+	write("main_test.go", "package main_test\n")
+	write("cmd/test/main.go", "package main\n\nfunc main() {}\n")
+	write("widget/widget.go", "package widget\n")
+	write("empty/README.md", "")
+
+	tests := []struct {
+		name    string
+		pkg     string
+		wantErr bool
+	}{
+		{name: "current dir", pkg: "."},
+		{name: "relative path", pkg: "./cmd/test"},
+		{name: "main go file", pkg: "./main.go"},
+		{name: "module import path", pkg: "github.com/fyne-io/fyne-example/cmd/test"},
+		{name: "module root import path", pkg: "github.com/fyne-io/fyne-example"},
+		{name: "external import path", pkg: "github.com/fyne-io/examples"},
+		{name: "typo", pkg: "./cmd/tset", wantErr: true},
+		{name: "not a main package", pkg: "./widget", wantErr: true},
+		{name: "no go files", pkg: "./empty", wantErr: true},
+		{name: "module import path typo", pkg: "github.com/fyne-io/fyne-example/cmd/tset", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Builder{
+				pkg:     tt.pkg,
+				workDir: dir,
+				gomod:   true,
+			}
+			err := d.preflight()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Builder.preflight() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_expandPackages(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fyne-cross")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	write := func(name string, content string) {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		err := ioutil.WriteFile(path, []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("main.go", "package main\n\nfunc main() {}\n")
+	write("cmd/app/main.go", "package main\n\nfunc main() {}\n")
+	write("cmd/tool/main.go", "package main\n\nfunc main() {}\n")
+	write("cmd/internal/util.go", "package internal\n")
+	write("cmd/testdata/main.go", "package main\n\nfunc main() {}\n")
+	write("build/linux-amd64/main.go", "package main\n\nfunc main() {}\n")
+	write("widget/widget.go", "package widget\n")
+
+	tests := []struct {
+		name    string
+		pkgs    []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "packages", pkgs: []string{".", "./cmd/app"}, want: []string{".", "./cmd/app"}},
+		{name: "pattern", pkgs: []string{"./cmd/..."}, want: []string{"./cmd/app", "./cmd/tool"}},
+		{name: "root pattern", pkgs: []string{"./..."}, want: []string{".", "./cmd/app", "./cmd/tool"}},
+		{name: "no main packages", pkgs: []string{"./widget/..."}, wantErr: true},
+		{name: "import path pattern", pkgs: []string{"github.com/fyne-io/fyne-example/..."}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandPackages(dir, tt.pkgs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expandPackages() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expandPackages() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_checkOutputNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		pkgs    []string
+		wantErr bool
+	}{
+		{name: "distinct names", pkgs: []string{".", "./cmd/app", "./cmd/tool"}},
+		{name: "same directory name", pkgs: []string{"./cmd/app", "./tools/app"}, wantErr: true},
+		{name: "current package named as the work dir", pkgs: []string{".", "./cmd/myapp"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkOutputNames("/home/fyne/myapp", tt.pkgs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkOutputNames() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_modulePath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fyne-cross")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{name: "module", content: "module fyne.io/fyne\n\nrequire (\n)\n", want: "fyne.io/fyne"},
+		{name: "quoted module", content: "module \"fyne.io/fyne\"\n", want: "fyne.io/fyne"},
+		{name: "missing module", content: "go 1.12\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, "go.mod")
+			ioutil.WriteFile(path, []byte(tt.content), 0644)
+			got, err := modulePath(path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("modulePath() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("modulePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuilder_checkTargetConstraints(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fyne-cross")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	write := func(name string, content string) {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		err := ioutil.WriteFile(path, []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("go.mod", "module github.com/fyne-io/fyne-example\n\ngo 1.12\n")
+	write("cmd/app/main.go", "package main\n\nfunc main() {}\n")
+	write("cmd/daemon/main_linux.go", "package main\n\nfunc main() {}\n")
+	write("cmd/tray/main.go", "// +build !windows\n\npackage main\n\nfunc main() {}\n")
+
+	tests := []struct {
+		name    string
+		pkg     string
+		target  string
+		wantErr bool
+	}{
+		{name: "no constraints", pkg: "./cmd/app", target: "windows/amd64"},
+		{name: "file name constraint", pkg: "./cmd/daemon", target: "linux/386"},
+		{name: "file name constraint excluded", pkg: "./cmd/daemon", target: "darwin/amd64", wantErr: true},
+		{name: "main go file excluded", pkg: "./cmd/daemon/main_linux.go", target: "windows/386", wantErr: true},
+		{name: "build tag", pkg: "./cmd/tray", target: "darwin/amd64"},
+		{name: "build tag excluded", pkg: "./cmd/tray", target: "windows/amd64", wantErr: true},
+		{name: "external package", pkg: "github.com/fyne-io/examples", target: "windows/amd64"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Builder{
+				pkg:     tt.pkg,
+				workDir: dir,
+				gomod:   true,
+			}
+			err := d.checkTargetConstraints(tt.target)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Builder.checkTargetConstraints() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
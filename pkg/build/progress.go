@@ -0,0 +1,65 @@
+package build
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// progressInterval is the interval the elapsed time of a running step is reported at
+var progressInterval = 30 * time.Second
+
+// syncWriter serializes the writes to w, so that the progress messages can be written
+// while the output of a command is copied to w
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
+
+// step runs fn as the long-running step described by title, i.e. an image pull or a dependency
+// download. The elapsed time is reported every progressInterval, so that a slow step does not
+// look like a hang, and once the step completes. The step is limited by the timeout option, see withTimeout
+func (d *Builder) step(title string, fn func() error) (time.Duration, error) {
+	d.startGroup(title)
+	defer d.endGroup()
+
+	start := time.Now()
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				fmt.Fprintf(d.stdout, "%s, %s elapsed...\n", title, formatElapsed(time.Since(start)))
+			}
+		}
+	}()
+
+	err := d.withTimeout(fn)
+	close(done)
+	wg.Wait()
+
+	elapsed := time.Since(start)
+	if err == nil {
+		fmt.Fprintf(d.stdout, "Done in %s\n", formatElapsed(elapsed))
+	}
+	return elapsed, err
+}
+
+// formatElapsed formats the elapsed time rounded to tenths of second
+func formatElapsed(elapsed time.Duration) string {
+	return elapsed.Round(100 * time.Millisecond).String()
+}
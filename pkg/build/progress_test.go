@@ -0,0 +1,45 @@
+package build
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuilder_step(t *testing.T) {
+	defer func(interval time.Duration) { progressInterval = interval }(progressInterval)
+	progressInterval = 10 * time.Millisecond
+
+	var buf bytes.Buffer
+	d := &Builder{stdout: &syncWriter{w: &buf}}
+	elapsed, err := d.step("Downloading dependencies", func() error {
+		time.Sleep(35 * time.Millisecond)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Builder.step() error = %v", err)
+	}
+	if elapsed < 35*time.Millisecond {
+		t.Errorf("Builder.step() elapsed = %v, want at least 35ms", elapsed)
+	}
+
+	got := buf.String()
+	if !strings.HasPrefix(got, "Downloading dependencies\nDownloading dependencies, ") {
+		t.Errorf("Builder.step() output = %q, want the elapsed time reported while running", got)
+	}
+	if !strings.Contains(got, "\nDone in ") {
+		t.Errorf("Builder.step() output = %q, want the elapsed time reported once done", got)
+	}
+
+	buf.Reset()
+	wantErr := errors.New("exit status 1")
+	_, err = d.step("Building for linux/amd64", func() error { return wantErr })
+	if err != wantErr {
+		t.Errorf("Builder.step() error = %v, want %v", err, wantErr)
+	}
+	if strings.Contains(buf.String(), "Done in") {
+		t.Errorf("Builder.step() output = %q, want no completion message on failure", buf.String())
+	}
+}
@@ -0,0 +1,35 @@
+package build
+
+import (
+	"strings"
+)
+
+// Proxy represents the proxy settings forwarded into the container
+type Proxy struct {
+	// HTTP is the proxy for HTTP requests
+	HTTP string
+	// HTTPS is the proxy for HTTPS requests
+	HTTPS string
+	// NoProxy is the comma separated list of hosts excluded from proxying
+	NoProxy string
+}
+
+// env returns the env variables used to set the proxy into the container.
+// Both the upper and lower case variants are set since tools differ on which one they honour
+func (p Proxy) env() []string {
+	env := []string{}
+	for _, v := range []struct {
+		name  string
+		value string
+	}{
+		{"HTTP_PROXY", p.HTTP},
+		{"HTTPS_PROXY", p.HTTPS},
+		{"NO_PROXY", p.NoProxy},
+	} {
+		if v.value == "" {
+			continue
+		}
+		env = append(env, v.name+"="+v.value, strings.ToLower(v.name)+"="+v.value)
+	}
+	return env
+}
@@ -0,0 +1,40 @@
+package build
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProxy_env(t *testing.T) {
+	tests := []struct {
+		name  string
+		proxy Proxy
+		want  []string
+	}{
+		{
+			name:  "no proxy",
+			proxy: Proxy{},
+			want:  []string{},
+		},
+		{
+			name: "all settings",
+			proxy: Proxy{
+				HTTP:    "http://proxy:3128",
+				HTTPS:   "http://proxy:3129",
+				NoProxy: "localhost,.example.com",
+			},
+			want: []string{
+				"HTTP_PROXY=http://proxy:3128", "http_proxy=http://proxy:3128",
+				"HTTPS_PROXY=http://proxy:3129", "https_proxy=http://proxy:3129",
+				"NO_PROXY=localhost,.example.com", "no_proxy=localhost,.example.com",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.proxy.env(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Proxy.env() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package build
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Publish publishes the artifacts described by the build manifest via the publisher
+func (d *Builder) Publish(ctx context.Context, p Publisher) error {
+	d.ctx = ctx
+
+	artifacts, err := d.readManifest()
+	if err != nil {
+		return fmt.Errorf("Cannot read the build manifest, run fyne-cross first %s", err)
+	}
+	if len(artifacts) == 0 {
+		return fmt.Errorf("No artifacts to publish")
+	}
+	return p.Publish(ctx, d.pluginInput(artifacts))
+}
+
+// NewUploadPublisher returns the publisher uploading the artifacts to the destination URL.
+// Supported destinations are:
+//   - s3://bucket/prefix?region=eu-west-1&endpoint=https://minio.example.com
+//   - sftp://user@host:port/dir
+//   - webdav://host/dir and webdavs://host/dir, for http and https respectively
+func NewUploadPublisher(dest string) (Publisher, error) {
+	u, err := url.Parse(dest)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid upload destination %s", err)
+	}
+
+	switch u.Scheme {
+	case "s3":
+		return &S3Publisher{
+			Bucket:   u.Host,
+			Prefix:   strings.TrimPrefix(u.Path, "/"),
+			Region:   u.Query().Get("region"),
+			Endpoint: u.Query().Get("endpoint"),
+		}, nil
+	case "sftp":
+		p := &SFTPPublisher{
+			Host: u.Hostname(),
+			User: u.User.Username(),
+			Dir:  u.Path,
+		}
+		if port := u.Port(); port != "" {
+			p.Port, err = strconv.Atoi(port)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid SFTP port %q", port)
+			}
+		}
+		return p, nil
+	case "webdav", "webdavs":
+		p := &WebDAVPublisher{}
+		if u.User != nil {
+			p.Username = u.User.Username()
+			p.Password, _ = u.User.Password()
+			u.User = nil
+		}
+		if u.Scheme == "webdavs" {
+			u.Scheme = "https"
+		} else {
+			u.Scheme = "http"
+		}
+		p.URL = u.String()
+		return p, nil
+	}
+	return nil, fmt.Errorf("Unsupported upload destination %q, supported schemes are s3, sftp, webdav and webdavs", dest)
+}
+
+// readManifest returns the artifacts described by the build manifest of the output folder
+func (d *Builder) readManifest() ([]Artifact, error) {
+	m, err := readManifestFile(filepath.Join(d.workDir, "build", manifestFile))
+	if err != nil {
+		return nil, err
+	}
+	return m.Artifacts, nil
+}
+
+// envOr returns value, if not empty, otherwise the value of the first env variable set
+func envOr(value string, names ...string) string {
+	if value != "" {
+		return value
+	}
+	for _, name := range names {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// assetName returns the name of the release asset for the artifact file.
+// The target subdir, if any, is dropped
+func assetName(file string) string {
+	return path.Base(file)
+}
+
+// doRequest sends the request and decodes the JSON response into out, if not nil.
+// An error is returned for unexpected status codes
+func doRequest(client *http.Client, req *http.Request, out interface{}, expected ...int) (int, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	ok := false
+	for _, code := range expected {
+		if resp.StatusCode == code {
+			ok = true
+			break
+		}
+	}
+	if !ok {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return resp.StatusCode, fmt.Errorf("%s %s: unexpected status %s %s", req.Method, req.URL.Path, resp.Status, body)
+	}
+
+	// decode successful responses only
+	if out == nil || resp.StatusCode == http.StatusNoContent || resp.StatusCode >= 300 {
+		return resp.StatusCode, nil
+	}
+	return resp.StatusCode, json.NewDecoder(resp.Body).Decode(out)
+}
+
+// openArtifact opens the artifact file returning its size, used to set the content length
+func openArtifact(buildDir string, a Artifact) (*os.File, int64, error) {
+	f, err := os.Open(filepath.Join(buildDir, filepath.FromSlash(a.File)))
+	if err != nil {
+		return nil, 0, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, 0, err
+	}
+	return f, info.Size(), nil
+}
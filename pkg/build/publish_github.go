@@ -0,0 +1,174 @@
+package build
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+func init() {
+	RegisterPublisher("github", &GitHubPublisher{})
+}
+
+// GitHubPublisher uploads the artifacts as assets of a GitHub release.
+// The release is created if it does not exist, assets with the same name are replaced.
+// Unset fields default to the GitHub Actions env variables
+type GitHubPublisher struct {
+	// Repo is the repository as owner/name. Default to $GITHUB_REPOSITORY
+	Repo string
+	// Tag is the release tag. Default to $GITHUB_REF_NAME
+	Tag string
+	// Token is the token used to authenticate. Default to $GITHUB_TOKEN
+	Token string
+	// APIURL is the GitHub API URL. Default to $GITHUB_API_URL or https://api.github.com
+	APIURL string
+	// Client is the HTTP client. Default to http.DefaultClient
+	Client *http.Client
+}
+
+// githubRelease represents the fields of a GitHub release used to upload the assets
+type githubRelease struct {
+	ID        int64  `json:"id"`
+	HTMLURL   string `json:"html_url"`
+	UploadURL string `json:"upload_url"`
+	Assets    []struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	} `json:"assets"`
+}
+
+// Publish uploads the artifacts as assets of the release
+func (p *GitHubPublisher) Publish(ctx context.Context, in PluginInput) error {
+	repo := envOr(p.Repo, "GITHUB_REPOSITORY")
+	tag := envOr(p.Tag, "GITHUB_REF_NAME")
+	token := envOr(p.Token, "GITHUB_TOKEN")
+	apiURL := strings.TrimSuffix(envOr(p.APIURL, "GITHUB_API_URL"), "/")
+	if apiURL == "" {
+		apiURL = "https://api.github.com"
+	}
+	if repo == "" || tag == "" || token == "" {
+		return fmt.Errorf("The GitHub repository, tag and token are required, see GITHUB_REPOSITORY, GITHUB_REF_NAME and GITHUB_TOKEN")
+	}
+
+	g := &githubClient{
+		ctx:    ctx,
+		client: p.Client,
+		token:  token,
+		base:   fmt.Sprintf("%s/repos/%s", apiURL, repo),
+	}
+	if g.client == nil {
+		g.client = http.DefaultClient
+	}
+
+	release, err := g.release(tag)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range in.Artifacts {
+		name := assetName(a.File)
+		for _, asset := range release.Assets {
+			if asset.Name == name {
+				err = g.deleteAsset(asset.ID)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		fmt.Fprintf(in.Stdout, "Uploading %s\n", name)
+		err = g.uploadAsset(release, in.BuildDir, a)
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(in.Stdout, "Published %s\n", release.HTMLURL)
+	return nil
+}
+
+// githubClient is the client of the GitHub releases API for a repository
+type githubClient struct {
+	ctx    context.Context
+	client *http.Client
+	token  string
+	// base is the API URL of the repository
+	base string
+}
+
+// newRequest returns an authenticated request
+func (g *githubClient) newRequest(method string, endpoint string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(g.ctx)
+	req.Header.Set("Authorization", "Bearer "+g.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	return req, nil
+}
+
+// release returns the release for tag, it is created if it does not exist
+func (g *githubClient) release(tag string) (githubRelease, error) {
+	release := githubRelease{}
+
+	req, err := g.newRequest(http.MethodGet, g.base+"/releases/tags/"+url.PathEscape(tag), nil)
+	if err != nil {
+		return release, err
+	}
+	status, err := doRequest(g.client, req, &release, http.StatusOK, http.StatusNotFound)
+	if err != nil || status == http.StatusOK {
+		return release, err
+	}
+
+	b, err := json.Marshal(map[string]string{"tag_name": tag, "name": tag})
+	if err != nil {
+		return release, err
+	}
+	req, err = g.newRequest(http.MethodPost, g.base+"/releases", bytes.NewReader(b))
+	if err != nil {
+		return release, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	_, err = doRequest(g.client, req, &release, http.StatusCreated)
+	return release, err
+}
+
+// deleteAsset deletes the release asset
+func (g *githubClient) deleteAsset(id int64) error {
+	req, err := g.newRequest(http.MethodDelete, fmt.Sprintf("%s/releases/assets/%d", g.base, id), nil)
+	if err != nil {
+		return err
+	}
+	_, err = doRequest(g.client, req, nil, http.StatusNoContent)
+	return err
+}
+
+// uploadAsset uploads the artifact as release asset
+func (g *githubClient) uploadAsset(release githubRelease, buildDir string, a Artifact) error {
+	f, size, err := openArtifact(buildDir, a)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// upload_url is an URI template, i.e. https://uploads.github.com/repos/o/r/releases/1/assets{?name,label}
+	uploadURL := release.UploadURL
+	if i := strings.Index(uploadURL, "{"); i >= 0 {
+		uploadURL = uploadURL[:i]
+	}
+	uploadURL += "?name=" + url.QueryEscape(assetName(a.File))
+
+	req, err := g.newRequest(http.MethodPost, uploadURL, f)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+	_, err = doRequest(g.client, req, nil, http.StatusCreated)
+	return err
+}
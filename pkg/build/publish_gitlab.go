@@ -0,0 +1,162 @@
+package build
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+func init() {
+	RegisterPublisher("gitlab", &GitLabPublisher{})
+}
+
+// gitlabPackageName is the name of the generic package the artifacts are uploaded to
+const gitlabPackageName = "fyne-cross"
+
+// GitLabPublisher uploads the artifacts to the generic package registry of a GitLab project
+// and links them to the release. The release is created if it does not exist.
+// Unset fields default to the GitLab CI/CD env variables
+type GitLabPublisher struct {
+	// Project is the project ID or path, i.e. group/name. Default to $CI_PROJECT_ID
+	Project string
+	// Tag is the release tag. Default to $CI_COMMIT_TAG
+	Tag string
+	// Token is the private token used to authenticate. Default to $GITLAB_TOKEN.
+	// When unset the $CI_JOB_TOKEN job token is used
+	Token string
+	// APIURL is the GitLab API URL. Default to $CI_API_V4_URL or https://gitlab.com/api/v4
+	APIURL string
+	// Client is the HTTP client. Default to http.DefaultClient
+	Client *http.Client
+}
+
+// Publish uploads the artifacts and links them to the release
+func (p *GitLabPublisher) Publish(ctx context.Context, in PluginInput) error {
+	project := envOr(p.Project, "CI_PROJECT_ID")
+	tag := envOr(p.Tag, "CI_COMMIT_TAG")
+	apiURL := strings.TrimSuffix(envOr(p.APIURL, "CI_API_V4_URL"), "/")
+	if apiURL == "" {
+		apiURL = "https://gitlab.com/api/v4"
+	}
+
+	g := &gitlabClient{
+		ctx:         ctx,
+		client:      p.Client,
+		tokenHeader: "PRIVATE-TOKEN",
+		token:       envOr(p.Token, "GITLAB_TOKEN"),
+		base:        fmt.Sprintf("%s/projects/%s", apiURL, url.PathEscape(project)),
+	}
+	if g.token == "" {
+		g.tokenHeader = "JOB-TOKEN"
+		g.token = envOr("", "CI_JOB_TOKEN")
+	}
+	if g.client == nil {
+		g.client = http.DefaultClient
+	}
+	if project == "" || tag == "" || g.token == "" {
+		return fmt.Errorf("The GitLab project, tag and token are required, see CI_PROJECT_ID, CI_COMMIT_TAG and GITLAB_TOKEN")
+	}
+
+	err := g.ensureRelease(tag)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range in.Artifacts {
+		name := assetName(a.File)
+		fmt.Fprintf(in.Stdout, "Uploading %s\n", name)
+		packageURL, err := g.uploadPackageFile(tag, in.BuildDir, a)
+		if err != nil {
+			return err
+		}
+		err = g.linkAsset(tag, name, packageURL)
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(in.Stdout, "Published release %s\n", tag)
+	return nil
+}
+
+// gitlabClient is the client of the GitLab API for a project
+type gitlabClient struct {
+	ctx         context.Context
+	client      *http.Client
+	tokenHeader string
+	token       string
+	// base is the API URL of the project
+	base string
+}
+
+// newRequest returns an authenticated request
+func (g *gitlabClient) newRequest(method string, endpoint string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(g.ctx)
+	req.Header.Set(g.tokenHeader, g.token)
+	return req, nil
+}
+
+// postJSON posts the value encoded as JSON to the endpoint
+func (g *gitlabClient) postJSON(endpoint string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	req, err := g.newRequest(http.MethodPost, endpoint, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	_, err = doRequest(g.client, req, nil, http.StatusCreated)
+	return err
+}
+
+// ensureRelease creates the release for tag if it does not exist
+func (g *gitlabClient) ensureRelease(tag string) error {
+	req, err := g.newRequest(http.MethodGet, g.base+"/releases/"+url.PathEscape(tag), nil)
+	if err != nil {
+		return err
+	}
+	status, err := doRequest(g.client, req, nil, http.StatusOK, http.StatusNotFound)
+	if err != nil || status == http.StatusOK {
+		return err
+	}
+	return g.postJSON(g.base+"/releases", map[string]string{"tag_name": tag, "name": tag})
+}
+
+// uploadPackageFile uploads the artifact to the generic package registry
+// using tag as package version and returns the package file URL
+func (g *gitlabClient) uploadPackageFile(tag string, buildDir string, a Artifact) (string, error) {
+	f, size, err := openArtifact(buildDir, a)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	packageURL := fmt.Sprintf("%s/packages/generic/%s/%s/%s", g.base, gitlabPackageName, url.PathEscape(tag), url.PathEscape(assetName(a.File)))
+	req, err := g.newRequest(http.MethodPut, packageURL, f)
+	if err != nil {
+		return "", err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+	_, err = doRequest(g.client, req, nil, http.StatusOK, http.StatusCreated)
+	return packageURL, err
+}
+
+// linkAsset links the package file URL to the release
+func (g *gitlabClient) linkAsset(tag string, name string, packageURL string) error {
+	return g.postJSON(g.base+"/releases/"+url.PathEscape(tag)+"/assets/links", map[string]string{
+		"name":      name,
+		"url":       packageURL,
+		"link_type": "package",
+	})
+}
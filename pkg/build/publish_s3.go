@@ -0,0 +1,218 @@
+package build
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Publisher uploads the artifacts to a S3 compatible object storage.
+// Objects are stored as <prefix>/<artifact file>.
+// Unset fields default to the AWS env variables
+type S3Publisher struct {
+	// Bucket is the bucket name
+	Bucket string
+	// Prefix is the key prefix of the objects, if any
+	Prefix string
+	// Region is the bucket region. Default to $AWS_REGION, $AWS_DEFAULT_REGION or us-east-1
+	Region string
+	// Endpoint is the storage endpoint, i.e. for S3 compatible storages.
+	// Default to $AWS_ENDPOINT_URL or https://s3.<region>.amazonaws.com
+	Endpoint string
+	// AccessKeyID is the access key. Default to $AWS_ACCESS_KEY_ID
+	AccessKeyID string
+	// SecretAccessKey is the secret key. Default to $AWS_SECRET_ACCESS_KEY
+	SecretAccessKey string
+	// SessionToken is the session token of temporary credentials. Default to $AWS_SESSION_TOKEN
+	SessionToken string
+	// Client is the HTTP client. Default to http.DefaultClient
+	Client *http.Client
+}
+
+// Publish uploads the artifacts as objects of the bucket
+func (p *S3Publisher) Publish(ctx context.Context, in PluginInput) error {
+	region := envOr(p.Region, "AWS_REGION", "AWS_DEFAULT_REGION")
+	if region == "" {
+		region = "us-east-1"
+	}
+	endpoint := strings.TrimSuffix(envOr(p.Endpoint, "AWS_ENDPOINT_URL"), "/")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+	creds := awsCredentials{
+		accessKeyID:     envOr(p.AccessKeyID, "AWS_ACCESS_KEY_ID"),
+		secretAccessKey: envOr(p.SecretAccessKey, "AWS_SECRET_ACCESS_KEY"),
+		sessionToken:    envOr(p.SessionToken, "AWS_SESSION_TOKEN"),
+		region:          region,
+		service:         "s3",
+	}
+	if p.Bucket == "" || creds.accessKeyID == "" || creds.secretAccessKey == "" {
+		return fmt.Errorf("The S3 bucket and credentials are required, see AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	for _, a := range in.Artifacts {
+		key := path.Join(p.Prefix, a.File)
+		fmt.Fprintf(in.Stdout, "Uploading s3://%s/%s\n", p.Bucket, key)
+		err := p.putObject(ctx, client, creds, endpoint+"/"+p.Bucket+"/"+key, in.BuildDir, a)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// putObject uploads the artifact to the object URL using path-style addressing
+func (p *S3Publisher) putObject(ctx context.Context, client *http.Client, creds awsCredentials, objectURL string, buildDir string, a Artifact) error {
+	f, size, err := openArtifact(buildDir, a)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// the payload hash is part of the signature
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return err
+	}
+	_, err = f.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPut, objectURL, f)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+	creds.sign(req, hex.EncodeToString(h.Sum(nil)), time.Now())
+	_, err = doRequest(client, req, nil, http.StatusOK)
+	return err
+}
+
+// awsCredentials represents the credentials used to sign the requests
+// with the AWS Signature Version 4
+type awsCredentials struct {
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+	region          string
+	service         string
+}
+
+// sign signs the request with the AWS Signature Version 4 at the time t.
+// The host and all the request headers are signed.
+// See https://docs.aws.amazon.com/general/latest/gr/sigv4_signing.html
+func (c awsCredentials) sign(req *http.Request, payloadHash string, t time.Time) {
+	t = t.UTC()
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if c.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", c.sessionToken)
+	}
+
+	// send the path as encoded for the signature
+	req.URL.RawPath = awsURIEncode(req.URL.Path, false)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := []string{}
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	canonicalHeaders := ""
+	for _, name := range names {
+		canonicalHeaders += name + ":" + headers[name] + "\n"
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	query := req.URL.Query()
+	keys := []string{}
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	canonicalQuery := []string{}
+	for _, k := range keys {
+		values := query[k]
+		sort.Strings(values)
+		for _, v := range values {
+			canonicalQuery = append(canonicalQuery, awsURIEncode(k, true)+"="+awsURIEncode(v, true))
+		}
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.RawPath,
+		strings.Join(canonicalQuery, "&"),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, c.region, c.service, "aws4_request"}, "/")
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(hash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.secretAccessKey), date)
+	key = hmacSHA256(key, c.region)
+	key = hmacSHA256(key, c.service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", c.accessKeyID, scope, signedHeaders, signature))
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data with key
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// awsURIEncode encodes s as required by the AWS signature: all the bytes except the
+// unreserved characters are percent-encoded. The slash is encoded only if encodeSlash is true
+func awsURIEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '.', c == '_', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,91 @@
+package build
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// SFTPPublisher uploads the artifacts to a SFTP server via the sftp command of OpenSSH.
+// Files are stored as <dir>/<artifact file>. The authentication is handled by ssh,
+// i.e. via the ssh agent or the keys configured for the host
+type SFTPPublisher struct {
+	// Host is the server host
+	Host string
+	// Port is the server port. Default to the ssh configuration
+	Port int
+	// User is the login user. Default to the ssh configuration
+	User string
+	// Dir is the remote directory, if any
+	Dir string
+}
+
+// Publish uploads the artifacts running sftp in batch mode
+func (p *SFTPPublisher) Publish(ctx context.Context, in PluginInput) error {
+	if p.Host == "" {
+		return fmt.Errorf("The SFTP host is required")
+	}
+
+	sftp, err := exec.LookPath("sftp")
+	if err != nil {
+		return fmt.Errorf("Missed requirement: sftp binary not found in PATH")
+	}
+
+	for _, a := range in.Artifacts {
+		fmt.Fprintf(in.Stdout, "Uploading %s\n", path.Join(p.Dir, a.File))
+	}
+
+	cmd := exec.CommandContext(ctx, sftp, p.args()...)
+	cmd.Stdin = bytes.NewBufferString(sftpBatch(p.Dir, in.BuildDir, in.Artifacts))
+	cmd.Stdout = in.Stdout
+	cmd.Stderr = in.Stderr
+	return cmd.Run()
+}
+
+// args returns the arguments of the sftp command reading the batch from stdin
+func (p *SFTPPublisher) args() []string {
+	args := []string{"-b", "-"}
+	if p.Port != 0 {
+		args = append(args, "-P", strconv.Itoa(p.Port))
+	}
+	dest := p.Host
+	if p.User != "" {
+		dest = p.User + "@" + p.Host
+	}
+	return append(args, dest)
+}
+
+// sftpBatch returns the sftp batch uploading the artifacts into dir.
+// Remote directories are created, the leading dash ignores the errors for the existing ones
+func sftpBatch(dir string, buildDir string, artifacts []Artifact) string {
+	var b strings.Builder
+	created := map[string]bool{}
+	for _, a := range artifacts {
+		remote := path.Join(dir, a.File)
+
+		// create the parent directories, if any
+		parts := strings.Split(path.Dir(remote), "/")
+		for i := range parts {
+			d := strings.Join(parts[:i+1], "/")
+			if d == "" || d == "." || created[d] {
+				continue
+			}
+			created[d] = true
+			fmt.Fprintf(&b, "-mkdir %s\n", sftpQuote(d))
+		}
+
+		local := filepath.Join(buildDir, filepath.FromSlash(a.File))
+		fmt.Fprintf(&b, "put %s %s\n", sftpQuote(local), sftpQuote(remote))
+	}
+	return b.String()
+}
+
+// sftpQuote quotes the path for the sftp batch
+func sftpQuote(s string) string {
+	return `"` + strings.Replace(strings.Replace(s, `\`, `\\`, -1), `"`, `\"`, -1) + `"`
+}
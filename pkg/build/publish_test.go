@@ -0,0 +1,117 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// publishTestDir returns a build folder containing the artifact files
+func publishTestDir(t *testing.T, artifacts []Artifact) string {
+	dir, err := ioutil.TempDir("", "fyne-cross-publish")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range artifacts {
+		path := filepath.Join(dir, filepath.FromSlash(a.File))
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = ioutil.WriteFile(path, []byte("fyne"), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestGitHubPublisher_Publish(t *testing.T) {
+	artifacts := []Artifact{
+		{Target: "linux/amd64", File: "fyne-linux-amd64"},
+		{Target: "windows/amd64", File: "windows-amd64/fyne-windows-amd64.exe"},
+	}
+	dir := publishTestDir(t, artifacts)
+	defer os.RemoveAll(dir)
+
+	requests := []string{}
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet:
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/repos/fyne-io/fyne/releases":
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"id":1,"upload_url":"%s/upload/1/assets{?name,label}","assets":[]}`, srv.URL)
+		default:
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer srv.Close()
+
+	p := &GitHubPublisher{Repo: "fyne-io/fyne", Tag: "v1.0.0", Token: "secret", APIURL: srv.URL}
+	err := p.Publish(context.Background(), PluginInput{BuildDir: dir, Artifacts: artifacts, Stdout: ioutil.Discard})
+	if err != nil {
+		t.Fatalf("GitHubPublisher.Publish() error = %v", err)
+	}
+
+	want := []string{
+		"GET /repos/fyne-io/fyne/releases/tags/v1.0.0",
+		"POST /repos/fyne-io/fyne/releases",
+		"POST /upload/1/assets?name=fyne-linux-amd64",
+		"POST /upload/1/assets?name=fyne-windows-amd64.exe",
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("GitHubPublisher.Publish() requests = %v, want %v", requests, want)
+	}
+}
+
+func TestGitLabPublisher_Publish(t *testing.T) {
+	artifacts := []Artifact{
+		{Target: "linux/amd64", File: "fyne-linux-amd64"},
+	}
+	dir := publishTestDir(t, artifacts)
+	defer os.RemoveAll(dir)
+
+	requests := []string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		if r.Header.Get("PRIVATE-TOKEN") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer srv.Close()
+
+	p := &GitLabPublisher{Project: "fyne-io/fyne", Tag: "v1.0.0", Token: "secret", APIURL: srv.URL}
+	err := p.Publish(context.Background(), PluginInput{BuildDir: dir, Artifacts: artifacts, Stdout: ioutil.Discard})
+	if err != nil {
+		t.Fatalf("GitLabPublisher.Publish() error = %v", err)
+	}
+
+	want := []string{
+		"GET /projects/fyne-io%2Ffyne/releases/v1.0.0",
+		"PUT /projects/fyne-io%2Ffyne/packages/generic/fyne-cross/v1.0.0/fyne-linux-amd64",
+		"POST /projects/fyne-io%2Ffyne/releases/v1.0.0/assets/links",
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("GitLabPublisher.Publish() requests = %v, want %v", requests, want)
+	}
+}
@@ -0,0 +1,120 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// WebDAVPublisher uploads the artifacts to a WebDAV server.
+// Files are stored as <url>/<artifact file>, the missing collections are created
+type WebDAVPublisher struct {
+	// URL is the URL of the collection the artifacts are uploaded to
+	URL string
+	// Username is the basic authentication user. Default to $WEBDAV_USERNAME
+	Username string
+	// Password is the basic authentication password. Default to $WEBDAV_PASSWORD
+	Password string
+	// Client is the HTTP client. Default to http.DefaultClient
+	Client *http.Client
+}
+
+// Publish uploads the artifacts via PUT requests
+func (p *WebDAVPublisher) Publish(ctx context.Context, in PluginInput) error {
+	if p.URL == "" {
+		return fmt.Errorf("The WebDAV URL is required")
+	}
+
+	w := &webdavClient{
+		ctx:      ctx,
+		client:   p.Client,
+		base:     strings.TrimSuffix(p.URL, "/"),
+		username: envOr(p.Username, "WEBDAV_USERNAME"),
+		password: envOr(p.Password, "WEBDAV_PASSWORD"),
+		created:  map[string]bool{},
+	}
+	if w.client == nil {
+		w.client = http.DefaultClient
+	}
+
+	for _, a := range in.Artifacts {
+		fmt.Fprintf(in.Stdout, "Uploading %s/%s\n", w.base, a.File)
+		err := w.mkcolAll(path.Dir(a.File))
+		if err != nil {
+			return err
+		}
+		err = w.put(in.BuildDir, a)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// webdavClient is the client of a WebDAV collection
+type webdavClient struct {
+	ctx      context.Context
+	client   *http.Client
+	base     string
+	username string
+	password string
+	// created are the collections already created
+	created map[string]bool
+}
+
+// newRequest returns a request for the path relative to the base collection
+func (w *webdavClient) newRequest(method string, p string) (*http.Request, error) {
+	req, err := http.NewRequest(method, w.base+"/"+p, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(w.ctx)
+	if w.username != "" || w.password != "" {
+		req.SetBasicAuth(w.username, w.password)
+	}
+	return req, nil
+}
+
+// mkcolAll creates the collection dir along with the parents, if any
+func (w *webdavClient) mkcolAll(dir string) error {
+	if dir == "." || dir == "" || w.created[dir] {
+		return nil
+	}
+	err := w.mkcolAll(path.Dir(dir))
+	if err != nil {
+		return err
+	}
+
+	req, err := w.newRequest("MKCOL", dir+"/")
+	if err != nil {
+		return err
+	}
+	// 405 Method Not Allowed is returned when the collection already exists
+	_, err = doRequest(w.client, req, nil, http.StatusCreated, http.StatusMethodNotAllowed)
+	if err != nil {
+		return err
+	}
+	w.created[dir] = true
+	return nil
+}
+
+// put uploads the artifact file
+func (w *webdavClient) put(buildDir string, a Artifact) error {
+	f, size, err := openArtifact(buildDir, a)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	req, err := w.newRequest(http.MethodPut, a.File)
+	if err != nil {
+		return err
+	}
+	req.Body = f
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+	_, err = doRequest(w.client, req, nil, http.StatusOK, http.StatusCreated, http.StatusNoContent)
+	return err
+}
@@ -0,0 +1,41 @@
+package build
+
+import (
+	"fmt"
+	"time"
+)
+
+// retryDelay is the delay before the first retry, doubled on each further retry up to maxRetryDelay
+var retryDelay = 2 * time.Second
+
+// maxRetryDelay is the maximum delay between the retries
+var maxRetryDelay = 30 * time.Second
+
+// retry runs the network dependent operation fn described by what, i.e. "pull the image",
+// retrying it up to the retries option times with exponential backoff.
+// It stops retrying when the context of the running operation is done
+func (d *Builder) retry(what string, fn func() error) error {
+	delay := retryDelay
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= d.retries {
+			return err
+		}
+
+		fmt.Fprintf(d.stdout, "Cannot %s %s, retrying in %s (%d/%d)\n", what, err, delay, attempt+1, d.retries)
+		if d.ctx != nil {
+			select {
+			case <-d.ctx.Done():
+				return err
+			case <-time.After(delay):
+			}
+		} else {
+			time.Sleep(delay)
+		}
+
+		delay *= 2
+		if delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+	}
+}
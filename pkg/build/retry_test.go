@@ -0,0 +1,62 @@
+package build
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestBuilder_retry(t *testing.T) {
+	defer func(delay time.Duration) { retryDelay = delay }(retryDelay)
+	retryDelay = time.Millisecond
+
+	errFailed := errors.New("exit status 1")
+	tests := []struct {
+		name         string
+		retries      int
+		failures     int
+		wantAttempts int
+		wantErr      bool
+	}{
+		{name: "no failures", retries: 3, failures: 0, wantAttempts: 1},
+		{name: "transient failures", retries: 3, failures: 2, wantAttempts: 3},
+		{name: "retries exhausted", retries: 2, failures: 5, wantAttempts: 3, wantErr: true},
+		{name: "no retries", retries: 0, failures: 1, wantAttempts: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Builder{retries: tt.retries, stdout: ioutil.Discard}
+			attempts := 0
+			err := d.retry("pull the image", func() error {
+				attempts++
+				if attempts <= tt.failures {
+					return errFailed
+				}
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Builder.retry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("Builder.retry() attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestBuilder_retry_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	d := &Builder{retries: 3, ctx: ctx, stdout: ioutil.Discard}
+	attempts := 0
+	err := d.retry("pull the image", func() error {
+		attempts++
+		return errors.New("exit status 1")
+	})
+	if err == nil || attempts != 1 {
+		t.Errorf("Builder.retry() = %v after %d attempts, want an error after 1 attempt", err, attempts)
+	}
+}
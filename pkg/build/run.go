@@ -0,0 +1,126 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Run runs the linux build of the first target into a docker container passing appArgs
+// to the application. The X11 and Wayland sockets and the GPU devices of the host are
+// forwarded to the container
+func (d *Builder) Run(ctx context.Context, appArgs []string) error {
+	d.ctx = ctx
+
+	target := d.targets[0]
+	if !isNativeTarget(target) {
+		return fmt.Errorf("Cannot run the %s target, only the linux/amd64 and linux/386 targets are supported", target)
+	}
+
+	t, err := d.targetOutput(target)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stat(filepath.Join(d.workDir, "build", t))
+	if err != nil {
+		return fmt.Errorf("Cannot find the %s build, run fyne-cross --targets=%s first", target, target)
+	}
+
+	err = d.checkRequirements()
+	if err != nil {
+		return err
+	}
+
+	err = d.writeEnvFile()
+	if err != nil {
+		return err
+	}
+	defer d.removeEnvFile()
+
+	err = d.ensureImage()
+	if err != nil {
+		return err
+	}
+
+	err = d.exec(d.runArgs(target, hostDisplay(), appArgs))
+	if d.canceled() {
+		d.removeContainers()
+	}
+	return err
+}
+
+// displayOpts represents the host display settings forwarded into the container
+type displayOpts struct {
+	// x11Display is the X11 display, i.e. :0
+	x11Display string
+	// xAuthority is the X11 authority file
+	xAuthority string
+	// waylandDisplay is the Wayland display socket name, i.e. wayland-0
+	waylandDisplay string
+	// xdgRuntimeDir is the directory containing the Wayland display socket
+	xdgRuntimeDir string
+	// dri is true when the host exposes the GPU devices
+	dri bool
+}
+
+// hostDisplay returns the display settings of the host
+func hostDisplay() displayOpts {
+	_, err := os.Stat("/dev/dri")
+	return displayOpts{
+		x11Display:     os.Getenv("DISPLAY"),
+		xAuthority:     os.Getenv("XAUTHORITY"),
+		waylandDisplay: os.Getenv("WAYLAND_DISPLAY"),
+		xdgRuntimeDir:  os.Getenv("XDG_RUNTIME_DIR"),
+		dri:            err == nil,
+	}
+}
+
+// args returns the arguments used to forward the display settings into the container
+func (d displayOpts) args(volume func(string, string, ...string) string) []string {
+	args := []string{}
+
+	if d.x11Display != "" {
+		args = append(args, "-e", "DISPLAY="+d.x11Display, "-v", volume("/tmp/.X11-unix", "/tmp/.X11-unix"))
+		if d.xAuthority != "" {
+			args = append(args, "-e", "XAUTHORITY=/tmp/.Xauthority", "-v", volume(d.xAuthority, "/tmp/.Xauthority", "ro"))
+		}
+	}
+
+	if d.waylandDisplay != "" && d.xdgRuntimeDir != "" {
+		socket := d.xdgRuntimeDir + "/" + d.waylandDisplay
+		args = append(args,
+			"-e", "WAYLAND_DISPLAY="+d.waylandDisplay,
+			"-e", "XDG_RUNTIME_DIR=/tmp/xdg",
+			"-v", volume(socket, "/tmp/xdg/"+d.waylandDisplay),
+		)
+	}
+
+	if d.dri {
+		args = append(args, "--device", "/dev/dri")
+	}
+	return args
+}
+
+// runArgs returns the arguments used to run the target output into a docker container
+func (d *Builder) runArgs(target string, display displayOpts, appArgs []string) []string {
+	args := []string{
+		"run", "--rm", "-t",
+		"-w", "/app/build",
+		"-v", d.volume(d.workDir+"/build", "/app/build", "ro"),
+	}
+
+	// run as the current user to access the display sockets
+	if u := d.containerUser(); u != "" {
+		args = append(args, "-e", "fyne_user="+u)
+	}
+
+	args = append(args, display.args(d.volume)...)
+
+	// export the env variables of the env file, of the env option and the proxy settings
+	args = append(args, d.envArgs()...)
+
+	t, _ := d.targetOutput(target)
+	args = append(args, d.imageName(), "./"+t)
+	return append(args, appArgs...)
+}
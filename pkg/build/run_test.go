@@ -0,0 +1,70 @@
+package build
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuilder_runArgs(t *testing.T) {
+	type args struct {
+		target  string
+		display displayOpts
+		appArgs []string
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "no display",
+			args: args{
+				target: "linux/amd64",
+			},
+			want: []string{
+				"run", "--rm", "-t",
+				"-w", "/app/build",
+				"-v", "/home/fyne/build:/app/build:ro",
+				"-e", "fyne_user=1000",
+				DefaultImage, "./test-linux-amd64",
+			},
+		},
+		{
+			name: "x11, wayland and gpu",
+			args: args{
+				target: "linux/386",
+				display: displayOpts{
+					x11Display:     ":0",
+					xAuthority:     "/run/user/1000/gdm/Xauthority",
+					waylandDisplay: "wayland-0",
+					xdgRuntimeDir:  "/run/user/1000",
+					dri:            true,
+				},
+				appArgs: []string{"--debug"},
+			},
+			want: []string{
+				"run", "--rm", "-t",
+				"-w", "/app/build",
+				"-v", "/home/fyne/build:/app/build:ro",
+				"-e", "fyne_user=1000",
+				"-e", "DISPLAY=:0", "-v", "/tmp/.X11-unix:/tmp/.X11-unix",
+				"-e", "XAUTHORITY=/tmp/.Xauthority", "-v", "/run/user/1000/gdm/Xauthority:/tmp/.Xauthority:ro",
+				"-e", "WAYLAND_DISPLAY=wayland-0", "-e", "XDG_RUNTIME_DIR=/tmp/xdg", "-v", "/run/user/1000/wayland-0:/tmp/xdg/wayland-0",
+				"--device", "/dev/dri",
+				DefaultImage, "./test-linux-386", "--debug",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Builder{
+				workDir: "/home/fyne",
+				output:  "test",
+				uid:     "1000",
+			}
+			if got := d.runArgs(tt.args.target, tt.args.display, tt.args.appArgs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Builder.runArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package build
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// sbomExt is the extension appended to the artifact name for the SBOM file
+const sbomExt = ".cdx.json"
+
+// goListModulesFormat is the go list template used to print the module providing a package.
+// Output lines are in the form "path version"
+const goListModulesFormat = "{{with .Module}}{{.Path}} {{.Version}}{{end}}"
+
+// sbom represents a CycloneDX Software Bill of Materials
+// see https://cyclonedx.org/docs/1.4/json/
+type sbom struct {
+	BOMFormat   string          `json:"bomFormat"`
+	SpecVersion string          `json:"specVersion"`
+	Version     int             `json:"version"`
+	Metadata    sbomMetadata    `json:"metadata"`
+	Components  []sbomComponent `json:"components"`
+}
+
+type sbomMetadata struct {
+	Timestamp time.Time     `json:"timestamp"`
+	Tools     []sbomTool    `json:"tools"`
+	Component sbomComponent `json:"component"`
+}
+
+type sbomTool struct {
+	Name string `json:"name"`
+}
+
+type sbomComponent struct {
+	Type       string         `json:"type"`
+	Name       string         `json:"name"`
+	Version    string         `json:"version,omitempty"`
+	PURL       string         `json:"purl,omitempty"`
+	Properties []sbomProperty `json:"properties,omitempty"`
+}
+
+type sbomProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// parseModules parses the output of go list using goListModulesFormat and returns the dependency
+// components sorted by name. The main module, that has no version, is skipped
+func parseModules(out string) []sbomComponent {
+	seen := map[string]bool{}
+	components := []sbomComponent{}
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.Fields(line)
+		if len(parts) != 2 || seen[parts[0]] {
+			continue
+		}
+		seen[parts[0]] = true
+		components = append(components, sbomComponent{
+			Type:    "library",
+			Name:    parts[0],
+			Version: parts[1],
+			PURL:    fmt.Sprintf("pkg:golang/%s@%s", parts[0], parts[1]),
+		})
+	}
+	sort.Slice(components, func(i, j int) bool {
+		return components[i].Name < components[j].Name
+	})
+	return components
+}
+
+// writeSBOM generates the SBOM for target and writes it next to the target output
+func (d *Builder) writeSBOM(target string) error {
+	args := d.goListModulesArgs(target)
+	if d.verbose {
+		fmt.Fprintf(d.stdout, "docker %s\n", strings.Join(args, " "))
+	}
+
+	var stdout bytes.Buffer
+	cmd := d.docker(args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = d.stderr
+	err := cmd.Run()
+	if err != nil {
+		return err
+	}
+
+	targetOutput, err := d.targetOutput(target)
+	if err != nil {
+		return err
+	}
+
+	bom := sbom{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.4",
+		Version:     1,
+		Metadata: sbomMetadata{
+			Timestamp: time.Now().UTC(),
+			Tools:     []sbomTool{{Name: "fyne-cross"}},
+			Component: sbomComponent{
+				Type: "application",
+				Name: filepath.Base(targetOutput),
+				Properties: []sbomProperty{
+					{Name: "fyne-cross:target", Value: target},
+				},
+			},
+		},
+		Components: parseModules(stdout.String()),
+	}
+
+	b, err := json.MarshalIndent(bom, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(d.workDir, "build", targetOutput+sbomExt), b, 0644)
+}
+
+// goListModulesArgs returns the arguments for the "go list" command used to
+// collect the module dependencies for target
+func (d *Builder) goListModulesArgs(target string) []string {
+	return d.execArgs(d.targetEnv(target), []string{d.goCmd(), "list", "-deps", "-f", goListModulesFormat, d.pkg})
+}
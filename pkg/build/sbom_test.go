@@ -0,0 +1,66 @@
+package build
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseModules(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want []sbomComponent
+	}{
+		{
+			name: "no modules",
+			out:  "",
+			want: []sbomComponent{},
+		},
+		{
+			name: "main module is skipped, duplicates removed and sorted",
+			out: "github.com/fyne-io/fyne-example \r\n" +
+				"fyne.io/fyne v1.1.2\r\n" +
+				"github.com/go-gl/glfw v0.0.0-20190409004039-e6da0acd62b1\r\n" +
+				"fyne.io/fyne v1.1.2\r\n",
+			want: []sbomComponent{
+				{
+					Type:    "library",
+					Name:    "fyne.io/fyne",
+					Version: "v1.1.2",
+					PURL:    "pkg:golang/fyne.io/fyne@v1.1.2",
+				},
+				{
+					Type:    "library",
+					Name:    "github.com/go-gl/glfw",
+					Version: "v0.0.0-20190409004039-e6da0acd62b1",
+					PURL:    "pkg:golang/github.com/go-gl/glfw@v0.0.0-20190409004039-e6da0acd62b1",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseModules(tt.out); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseModules() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuilder_goListModulesArgs(t *testing.T) {
+	d := &Builder{
+		pkg:         "fyne-io/fyne-example",
+		containerID: "fyne-cross",
+	}
+	want := []string{
+		"exec",
+		"-e", "CGO_ENABLED=1",
+		"-e", "GOOS=linux", "-e", "GOARCH=amd64", "-e", "CC=gcc",
+		"-e", "GOCACHE=/go/gocache/linux-amd64",
+		"fyne-cross",
+		"go", "list", "-deps", "-f", "{{with .Module}}{{.Path}} {{.Version}}{{end}}", "fyne-io/fyne-example",
+	}
+	if got := d.goListModulesArgs("linux/amd64"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Builder.goListModulesArgs() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,51 @@
+package build
+
+import (
+	"context"
+	"fmt"
+)
+
+// Shell opens an interactive shell into a docker container with the same
+// mounts and env used to build the first target, i.e. to debug CGO and linker issues
+func (d *Builder) Shell(ctx context.Context) error {
+	d.ctx = ctx
+
+	err := d.checkRequirements()
+	if err != nil {
+		return err
+	}
+
+	err = d.writeEnvFile()
+	if err != nil {
+		return err
+	}
+	defer d.removeEnvFile()
+
+	err = d.ensureImage()
+	if err != nil {
+		return err
+	}
+
+	err = d.downloadToolchain()
+	if err != nil {
+		return err
+	}
+
+	return d.exec(d.shellArgs(d.targets[0]))
+}
+
+// shellArgs returns the arguments used to open an interactive shell into a
+// docker container with the same mounts and env used to build target.
+// The go binary of the downloaded toolchain, if any, takes precedence over the image one
+func (d *Builder) shellArgs(target string) []string {
+	args := append(d.defaultArgs(), "-it")
+	args = append(args, d.moduleCacheArgs()...)
+	for _, env := range d.targetEnv(target) {
+		args = append(args, "-e", env)
+	}
+	if d.toolchain != "" {
+		// PATH is expanded into the container, the image one is unknown to the host
+		return append(args, d.imageName(), "bash", "-c", fmt.Sprintf("PATH=%s/bin:$PATH exec bash", d.goroot()))
+	}
+	return append(args, d.imageName(), "bash")
+}
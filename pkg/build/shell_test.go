@@ -0,0 +1,46 @@
+package build
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuilder_shellArgs(t *testing.T) {
+	d := &Builder{
+		workDir:  "/home/fyne",
+		cacheDir: "/tmp/cache",
+		rootless: true,
+	}
+	want := []string{
+		"run", "--rm",
+		"-w", "/app",
+		"-v", "/home/fyne:/app",
+		"-v", "/tmp/cache/fyne-cross:/go",
+		"-it",
+		"-v", "/tmp/cache/fyne-cross/pkg/mod:/go/pkg/mod:ro",
+		"-e", "CGO_ENABLED=1",
+		"-e", "GOOS=windows", "-e", "GOARCH=amd64", "-e", "CC=x86_64-w64-mingw32-gcc",
+		"-e", "GOCACHE=/go/gocache/windows-amd64",
+		DefaultImage, "bash",
+	}
+	if got := d.shellArgs("windows/amd64"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Builder.shellArgs() = %v, want %v", got, want)
+	}
+}
+
+func TestBuilder_shellArgs_toolchain(t *testing.T) {
+	d := &Builder{
+		workDir:   "/home/fyne",
+		cacheDir:  "/tmp/cache",
+		rootless:  true,
+		toolchain: "1.16.3",
+	}
+	got := d.shellArgs("linux/amd64")
+	want := []string{DefaultImage, "bash", "-c", "PATH=/go/toolchains/go1.16.3/bin:$PATH exec bash"}
+	if !reflect.DeepEqual(got[len(got)-len(want):], want) {
+		t.Errorf("Builder.shellArgs() = %v, want suffix %v", got, want)
+	}
+	if !containsArg(got, "GOROOT=/go/toolchains/go1.16.3") {
+		t.Errorf("Builder.shellArgs() = %v, want GOROOT of the toolchain", got)
+	}
+}
@@ -0,0 +1,48 @@
+package build
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	statusBuilt    = "built"
+	statusUpToDate = "up-to-date"
+	statusFailed   = "failed"
+	statusSkipped  = "skipped"
+)
+
+// targetResult represents the result of the build for a target
+type targetResult struct {
+	target string
+	pkg    string
+	// output is the target output relative to the build folder
+	output string
+	status string
+	// elapsed is the build time, zero when the target is not built
+	elapsed time.Duration
+	err     error
+}
+
+// printSummary prints the per target summary table of the build along with the total build time
+func printSummary(w io.Writer, results []targetResult, total time.Duration) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Summary:")
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "TARGET\tSTATUS\tTIME\tOUTPUT")
+	for _, r := range results {
+		status := r.status
+		if r.err != nil {
+			status = statusFailed
+		}
+		elapsed := "-"
+		if r.elapsed > 0 {
+			elapsed = formatElapsed(r.elapsed)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.target, status, elapsed, r.output)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "Total time: %s\n", formatElapsed(total))
+}
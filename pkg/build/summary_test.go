@@ -0,0 +1,30 @@
+package build
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_printSummary(t *testing.T) {
+	results := []targetResult{
+		{target: "linux/amd64", output: "test-linux-amd64", status: statusBuilt, elapsed: 83250 * time.Millisecond},
+		{target: "darwin/amd64", output: "test-darwin-amd64", status: statusUpToDate},
+		{target: "windows/amd64", output: "test-windows-amd64.exe", err: errors.New("exit status 2")},
+	}
+	want := `
+Summary:
+TARGET         STATUS      TIME     OUTPUT
+linux/amd64    built       1m23.3s  test-linux-amd64
+darwin/amd64   up-to-date  -        test-darwin-amd64
+windows/amd64  failed      -        test-windows-amd64.exe
+Total time: 2m0s
+`
+
+	var buf bytes.Buffer
+	printSummary(&buf, results, 2*time.Minute)
+	if got := buf.String(); got != want {
+		t.Errorf("printSummary() = %q, want %q", got, want)
+	}
+}
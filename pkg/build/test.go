@@ -0,0 +1,118 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// TestOptions represents the options passed through to go test
+type TestOptions struct {
+	// Run is the regular expression used to select the tests to run
+	Run string
+	// Count is the number of times to run each test. Default to the go test default
+	Count int
+	// CoverProfile is the coverage profile file. The target is appended to the file name
+	CoverProfile string
+}
+
+// Test runs the go tests of pkgs for all the targets into a container session.
+// Tests are executed only for the linux targets but linux/arm, for the other targets they are compiled only
+func (d *Builder) Test(ctx context.Context, pkgs []string, opts TestOptions) error {
+	d.ctx = ctx
+
+	if len(pkgs) == 0 {
+		pkgs = []string{"./..."}
+	}
+
+	err := d.checkRequirements()
+	if err != nil {
+		return err
+	}
+
+	err = d.writeEnvFile()
+	if err != nil {
+		return err
+	}
+	defer d.removeEnvFile()
+
+	err = d.ensureImage()
+	if err != nil {
+		return err
+	}
+
+	err = d.start()
+	if err != nil {
+		return fmt.Errorf("Cannot start the build container %s", err)
+	}
+	defer d.stop()
+
+	err = d.downloadToolchain()
+	if err != nil {
+		return err
+	}
+
+	_, err = d.step("Downloading dependencies", d.goGet)
+	if err != nil {
+		return err
+	}
+
+	for _, target := range d.targets {
+		title := fmt.Sprintf("Testing for %s", target)
+		if !isNativeTarget(target) {
+			title += " (compile only)"
+		}
+		d.startGroup(title)
+		err = d.exec(d.goTestArgs(target, pkgs, opts))
+		d.endGroup()
+		if err != nil {
+			return fmt.Errorf("Tests failed for %s %s", target, err)
+		}
+	}
+	return nil
+}
+
+// isNativeTarget returns true if the binaries built for target can run into the container
+func isNativeTarget(target string) bool {
+	return strings.HasPrefix(target, "linux/") && target != "linux/arm"
+}
+
+// coverProfileFor returns the coverage profile file for target
+// Example: cover.out -> cover-linux-amd64.out
+func coverProfileFor(coverProfile string, target string) string {
+	ext := filepath.Ext(coverProfile)
+	normalizedTarget := strings.Replace(target, "/", "-", -1)
+	return fmt.Sprintf("%s-%s%s", strings.TrimSuffix(coverProfile, ext), normalizedTarget, ext)
+}
+
+// goTestArgs returns the arguments for the "go test" command for target.
+// Test binaries that cannot run into the container are compiled only
+func (d *Builder) goTestArgs(target string, pkgs []string, opts TestOptions) []string {
+	testCmd := []string{d.goCmd(), "test"}
+
+	if d.verbose {
+		testCmd = append(testCmd, "-v")
+	}
+
+	if opts.Run != "" {
+		testCmd = append(testCmd, "-run", opts.Run)
+	}
+
+	if opts.Count > 0 {
+		testCmd = append(testCmd, "-count", strconv.Itoa(opts.Count))
+	}
+
+	if isNativeTarget(target) {
+		if opts.CoverProfile != "" {
+			testCmd = append(testCmd, "-coverprofile", coverProfileFor(opts.CoverProfile, target))
+		}
+	} else {
+		// compile only, the test binary is passed to true that exits successfully
+		testCmd = append(testCmd, "-exec", "true")
+	}
+
+	testCmd = append(testCmd, pkgs...)
+	return d.execArgs(d.targetEnv(target), testCmd)
+}
@@ -0,0 +1,86 @@
+package build
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuilder_goTestArgs(t *testing.T) {
+	type args struct {
+		target string
+		pkgs   []string
+		opts   TestOptions
+	}
+	tests := []struct {
+		name    string
+		verbose bool
+		args    args
+		want    []string
+	}{
+		{
+			name: "linux",
+			args: args{
+				target: "linux/amd64",
+				pkgs:   []string{"./..."},
+			},
+			want: []string{
+				"exec",
+				"-e", "CGO_ENABLED=1",
+				"-e", "GOOS=linux", "-e", "GOARCH=amd64", "-e", "CC=gcc",
+				"-e", "GOCACHE=/go/gocache/linux-amd64",
+				"fyne-cross",
+				"go", "test", "./...",
+			},
+		},
+		{
+			name:    "linux with options",
+			verbose: true,
+			args: args{
+				target: "linux/386",
+				pkgs:   []string{"./widget", "./theme"},
+				opts: TestOptions{
+					Run:          "TestApp",
+					Count:        1,
+					CoverProfile: "cover.out",
+				},
+			},
+			want: []string{
+				"exec",
+				"-e", "CGO_ENABLED=1",
+				"-e", "GOOS=linux", "-e", "GOARCH=386", "-e", "CC=gcc",
+				"-e", "GOCACHE=/go/gocache/linux-386",
+				"fyne-cross",
+				"go", "test", "-v", "-run", "TestApp", "-count", "1", "-coverprofile", "cover-linux-386.out", "./widget", "./theme",
+			},
+		},
+		{
+			name: "windows compile only",
+			args: args{
+				target: "windows/amd64",
+				pkgs:   []string{"./..."},
+				opts: TestOptions{
+					CoverProfile: "cover.out",
+				},
+			},
+			want: []string{
+				"exec",
+				"-e", "CGO_ENABLED=1",
+				"-e", "GOOS=windows", "-e", "GOARCH=amd64", "-e", "CC=x86_64-w64-mingw32-gcc",
+				"-e", "GOCACHE=/go/gocache/windows-amd64",
+				"fyne-cross",
+				"go", "test", "-exec", "true", "./...",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Builder{
+				verbose:     tt.verbose,
+				containerID: "fyne-cross",
+			}
+			if got := d.goTestArgs(tt.args.target, tt.args.pkgs, tt.args.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Builder.goTestArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package build
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+)
+
+// toolchainsDir is the container folder of the downloaded Go toolchains.
+// It is located into the cache volume so that the toolchains are downloaded only once
+const toolchainsDir = "/go/toolchains"
+
+// toolchainURL is the download URL of the Go toolchains. The tarball is for the container platform
+const toolchainURL = "https://dl.google.com/go/go%s.linux-amd64.tar.gz"
+
+// goVersionRegexp matches a Go release version, i.e. 1.16, 1.16.3 or 1.17rc1
+var goVersionRegexp = regexp.MustCompile(`^1\.[0-9]+(\.[0-9]+|(beta|rc)[0-9]+)?$`)
+
+// goMinorRegexp matches the minor version of a Go release version
+var goMinorRegexp = regexp.MustCompile(`^1\.([0-9]+)`)
+
+// checkGoVersion checks the Go version of the toolchain to download
+func checkGoVersion(version string) error {
+	if !goVersionRegexp.MatchString(version) {
+		return fmt.Errorf("Invalid Go version %q, expected a release version i.e. 1.16.3", version)
+	}
+	return nil
+}
+
+// goMinorVersion returns the minor version of the Go release version, i.e. 16 for 1.16.3.
+// Zero is returned for an empty or invalid version
+func goMinorVersion(version string) int {
+	m := goMinorRegexp.FindStringSubmatch(version)
+	if m == nil {
+		return 0
+	}
+	minor, _ := strconv.Atoi(m[1])
+	return minor
+}
+
+// goroot returns the GOROOT of the downloaded toolchain into the container, if any
+func (d *Builder) goroot() string {
+	if d.toolchain == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/go%s", toolchainsDir, d.toolchain)
+}
+
+// goCmd returns the go command into the container: the go binary of the downloaded toolchain, if any,
+// otherwise the go binary of the image
+func (d *Builder) goCmd() string {
+	if d.toolchain == "" {
+		return "go"
+	}
+	return d.goroot() + "/bin/go"
+}
+
+// goEnv returns the env variables pointing GOROOT to the downloaded toolchain, if any
+func (d *Builder) goEnv() []string {
+	if d.toolchain == "" {
+		return nil
+	}
+	return []string{"GOROOT=" + d.goroot()}
+}
+
+// toolchainScript returns the shell script downloading the toolchain into the container.
+// The tarball is checked against the published checksum and extracted into a temporary
+// folder moved into place once complete, so that an interrupted download is not reused.
+// The toolchain folder is touched on each use to track the last use for the cache prune
+func (d *Builder) toolchainScript() string {
+	url := fmt.Sprintf(toolchainURL, d.toolchain)
+	return fmt.Sprintf(`set -e
+[ -x %[1]s/bin/go ] && touch %[1]s && exit 0
+mkdir -p %[2]s
+tmp=$(mktemp -d %[2]s/.download-XXXXXX)
+trap 'rm -rf "$tmp"' EXIT
+curl -fsSL -o "$tmp/go.tar.gz" %[3]s
+echo "$(curl -fsSL %[3]s.sha256)  $tmp/go.tar.gz" | sha256sum -c -
+tar -xzf "$tmp/go.tar.gz" -C "$tmp"
+mv "$tmp/go" %[1]s
+touch %[1]s
+`, d.goroot(), toolchainsDir, url)
+}
+
+// downloadToolchain downloads the requested Go toolchain into the cache volume, if not already
+// downloaded. The download runs into a dedicated container holding the lock on the cache
+// directory, see goGet
+func (d *Builder) downloadToolchain() error {
+	if d.toolchain == "" {
+		return nil
+	}
+
+	lock, err := lockFile(filepath.Join(d.cacheDir, "fyne-cross", lockFileName))
+	if err != nil {
+		return fmt.Errorf("Cannot lock the cache directory %s", err)
+	}
+	defer lock.unlock()
+
+	_, err = d.step(fmt.Sprintf("Downloading Go %s", d.toolchain), func() error {
+		return d.retry(fmt.Sprintf("download Go %s", d.toolchain), func() error {
+			return d.exec(d.toolchainArgs())
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("Cannot download Go %s %s", d.toolchain, err)
+	}
+	return nil
+}
+
+// toolchainArgs returns the arguments to run the toolchain download script.
+// The script runs into a dedicated container since the session could have no network access
+func (d *Builder) toolchainArgs() []string {
+	return append(d.defaultArgs(), d.imageName(), "sh", "-c", d.toolchainScript())
+}
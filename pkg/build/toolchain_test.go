@@ -0,0 +1,73 @@
+package build
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_checkGoVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		wantErr bool
+	}{
+		{name: "minor", version: "1.16"},
+		{name: "patch", version: "1.16.3"},
+		{name: "release candidate", version: "1.17rc1"},
+		{name: "beta", version: "1.17beta1"},
+		{name: "go prefix", version: "go1.16.3", wantErr: true},
+		{name: "shell injection", version: "1.16;rm -rf /", wantErr: true},
+		{name: "empty", version: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkGoVersion(tt.version); (err != nil) != tt.wantErr {
+				t.Errorf("checkGoVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_goMinorVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    int
+	}{
+		{version: "1.18", want: 18},
+		{version: "1.16.3", want: 16},
+		{version: "1.18rc1", want: 18},
+		{version: "", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if got := goMinorVersion(tt.version); got != tt.want {
+				t.Errorf("goMinorVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuilder_goBuildArgs_toolchain(t *testing.T) {
+	d := &Builder{
+		pkg:         "fyne-io/fyne-example",
+		output:      "test",
+		toolchain:   "1.16.3",
+		containerID: "fyne-cross",
+	}
+	want := []string{
+		"exec",
+		"-e", "CGO_ENABLED=1",
+		"-e", "GOOS=linux", "-e", "GOARCH=amd64", "-e", "CC=gcc",
+		"-e", "GOCACHE=/go/gocache/linux-amd64",
+		"-e", "GOROOT=/go/toolchains/go1.16.3",
+		"fyne-cross",
+		"/go/toolchains/go1.16.3/bin/go", "build", "-o", "build/test-linux-amd64", "fyne-io/fyne-example",
+	}
+	got, err := d.goBuildArgs("linux/amd64")
+	if err != nil {
+		t.Fatalf("Builder.goBuildArgs() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Builder.goBuildArgs() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,85 @@
+package build
+
+import (
+	"fmt"
+	"os/user"
+	"strconv"
+)
+
+// containerHome is the home folder of the container user, that could have no entry
+// into the image passwd file. It is created by the docker entrypoint
+const containerHome = "/tmp/fyne-home"
+
+// isNumericID returns true if id is a numeric user or group id
+func isNumericID(id string) bool {
+	_, err := strconv.ParseUint(id, 10, 32)
+	return err == nil
+}
+
+// currentUser returns the numeric ids of the current user. Empty ids are returned
+// when the ids are not numeric, i.e. on Windows hosts
+func currentUser() (string, string) {
+	u, err := user.Current()
+	if err != nil || !isNumericID(u.Uid) || !isNumericID(u.Gid) {
+		return "", ""
+	}
+	return u.Uid, u.Gid
+}
+
+// resolveUser resolves the user and the group running the commands into the container
+// to numeric ids. The names are looked up on the host. The user defaults to the current user
+// and the group to the primary group of the user, if known, otherwise of the current user.
+// Empty ids are returned when neither the user nor the group is specified, see checkRequirements
+func resolveUser(name string, group string) (string, string, error) {
+	if name == "" && group == "" {
+		return "", "", nil
+	}
+
+	uid, gid := currentUser()
+	switch {
+	case name == "":
+		if uid == "" {
+			return "", "", fmt.Errorf("Cannot get the current user id, specify the user")
+		}
+	case isNumericID(name):
+		uid, gid = name, ""
+		if u, err := user.LookupId(name); err == nil && isNumericID(u.Gid) {
+			gid = u.Gid
+		}
+	default:
+		u, err := user.Lookup(name)
+		if err != nil {
+			return "", "", fmt.Errorf("Cannot find the user %s", err)
+		}
+		if !isNumericID(u.Uid) {
+			return "", "", fmt.Errorf("The user %q has not a numeric id", name)
+		}
+		uid, gid = u.Uid, u.Gid
+	}
+
+	switch {
+	case group == "":
+	case isNumericID(group):
+		gid = group
+	default:
+		g, err := user.LookupGroup(group)
+		if err != nil {
+			return "", "", fmt.Errorf("Cannot find the group %s", err)
+		}
+		gid = g.Gid
+	}
+
+	if !isNumericID(gid) {
+		_, gid = currentUser()
+	}
+	return uid, gid, nil
+}
+
+// containerUser returns the user running the commands into the container in the form uid:gid.
+// Empty means the container root user
+func (d *Builder) containerUser() string {
+	if d.uid == "" || d.gid == "" {
+		return d.uid
+	}
+	return d.uid + ":" + d.gid
+}
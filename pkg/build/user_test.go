@@ -0,0 +1,88 @@
+package build
+
+import (
+	"testing"
+)
+
+func Test_resolveUser(t *testing.T) {
+	uid, _ := currentUser()
+	if uid == "" {
+		t.Skip("the current user has not numeric ids")
+	}
+
+	tests := []struct {
+		name    string
+		user    string
+		group   string
+		wantUID string
+		wantGID string
+		wantErr bool
+	}{
+		{
+			name: "default",
+		},
+		{
+			name:    "group only",
+			group:   "2000",
+			wantUID: uid,
+			wantGID: "2000",
+		},
+		{
+			name:    "numeric user and group",
+			user:    "1500",
+			group:   "2000",
+			wantUID: "1500",
+			wantGID: "2000",
+		},
+		{
+			name:    "user by name",
+			user:    "root",
+			wantUID: "0",
+			wantGID: "0",
+		},
+		{
+			name:    "unknown user",
+			user:    "fyne-cross-unknown-user",
+			wantErr: true,
+		},
+		{
+			name:    "unknown group",
+			user:    "1500",
+			group:   "fyne-cross-unknown-group",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotUID, gotGID, err := resolveUser(tt.user, tt.group)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("resolveUser() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotUID != tt.wantUID || gotGID != tt.wantGID {
+				t.Errorf("resolveUser() = %v:%v, want %v:%v", gotUID, gotGID, tt.wantUID, tt.wantGID)
+			}
+		})
+	}
+}
+
+func TestBuilder_containerUser(t *testing.T) {
+	tests := []struct {
+		name string
+		uid  string
+		gid  string
+		want string
+	}{
+		{name: "root", want: ""},
+		{name: "user only", uid: "1000", want: "1000"},
+		{name: "user and group", uid: "1000", gid: "100", want: "1000:100"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Builder{uid: tt.uid, gid: tt.gid}
+			if got := d.containerUser(); got != tt.want {
+				t.Errorf("Builder.containerUser() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,310 @@
+package build
+
+import (
+	"bytes"
+	"context"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+)
+
+// VerifyOptions represents the options of the artifacts verification
+type VerifyOptions struct {
+	// RequireSignature fails the verification of the unsigned windows and darwin executables
+	RequireSignature bool
+}
+
+// lcCodeSignature is the Mach-O load command of the embedded code signature
+const lcCodeSignature = 0x1d
+
+// elfArchs, peArchs and machoArchs map the machine types of the file headers to the GOARCH
+var (
+	elfArchs = map[elf.Machine]string{
+		elf.EM_X86_64:  "amd64",
+		elf.EM_386:     "386",
+		elf.EM_ARM:     "arm",
+		elf.EM_AARCH64: "arm64",
+	}
+	peArchs = map[uint16]string{
+		pe.IMAGE_FILE_MACHINE_AMD64: "amd64",
+		pe.IMAGE_FILE_MACHINE_I386:  "386",
+		pe.IMAGE_FILE_MACHINE_ARMNT: "arm",
+		pe.IMAGE_FILE_MACHINE_ARM64: "arm64",
+	}
+	machoArchs = map[macho.Cpu]string{
+		macho.CpuAmd64: "amd64",
+		macho.Cpu386:   "386",
+		macho.CpuArm:   "arm",
+		macho.CpuArm64: "arm64",
+	}
+)
+
+// binaryInfo describes an executable as read from its file headers
+type binaryInfo struct {
+	// format is the executable format: elf, pe or macho
+	format string
+	// targets are the GOOS/GOARCH of the executable, more than one for the macOS universal binaries
+	targets []string
+	// signed is true if the executable embeds a code signature
+	signed bool
+}
+
+// hasTarget returns true if the executable is built for target
+func (b binaryInfo) hasTarget(target string) bool {
+	for _, t := range b.targets {
+		if t == target {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyResult represents the verification result of an artifact
+type verifyResult struct {
+	file      string
+	target    string
+	signature string
+	err       error
+}
+
+// Verify verifies the artifacts described by the build manifest: the checksums are recomputed, the
+// GOOS/GOARCH of the executables is read from the file headers and the embedded code signatures
+// are validated via osslsigncode for windows and codesign for darwin, when available on the host
+func (d *Builder) Verify(ctx context.Context, opts VerifyOptions) error {
+	d.ctx = ctx
+
+	artifacts, err := d.readManifest()
+	if err != nil {
+		return fmt.Errorf("Cannot read the build manifest, run fyne-cross first %s", err)
+	}
+	if len(artifacts) == 0 {
+		return fmt.Errorf("No artifacts to verify")
+	}
+
+	results := []verifyResult{}
+	failed := 0
+	for _, a := range artifacts {
+		res := d.verifyArtifact(a, opts)
+		if res.err != nil {
+			failed++
+		}
+		results = append(results, res)
+	}
+	printVerifySummary(d.stdout, results)
+
+	if failed > 0 {
+		return fmt.Errorf("Verification failed for %d of %d artifacts", failed, len(artifacts))
+	}
+	return nil
+}
+
+// verifyArtifact verifies the artifact a against the file into the build folder.
+// The headers and the signature are verified only for the executables built for a target
+func (d *Builder) verifyArtifact(a Artifact, opts VerifyOptions) verifyResult {
+	res := verifyResult{file: a.File, target: a.Target, signature: "-"}
+	file := filepath.Join(d.workDir, "build", filepath.FromSlash(a.File))
+
+	actual, err := newArtifact(a.Target, file)
+	if err != nil {
+		res.err = fmt.Errorf("Cannot read the artifact %s", err)
+		return res
+	}
+	if actual.Size != a.Size || actual.SHA256 != a.SHA256 {
+		res.err = fmt.Errorf("Checksum mismatch, got %s", actual.SHA256)
+		return res
+	}
+
+	// packages, manifests and c-archive libraries have no executable headers
+	if a.Target == "" || a.Package == "" || path.Ext(a.File) == ".a" {
+		return res
+	}
+
+	bin, err := readBinaryInfo(file)
+	if err != nil {
+		res.err = err
+		return res
+	}
+	if !bin.hasTarget(a.Target) {
+		res.err = fmt.Errorf("Executable built for %s", strings.Join(bin.targets, ","))
+		return res
+	}
+
+	if bin.format == "elf" {
+		return res
+	}
+	if !bin.signed {
+		res.signature = "unsigned"
+		if opts.RequireSignature {
+			res.err = fmt.Errorf("Missing code signature")
+		}
+		return res
+	}
+	res.signature, res.err = d.validateSignature(bin.format, file)
+	return res
+}
+
+// validateSignature validates the code signature of the executable file via the host tools:
+// osslsigncode for the Authenticode signatures and codesign for the darwin ones.
+// The signature is reported as not validated if the tool is not available
+func (d *Builder) validateSignature(format string, file string) (string, error) {
+	var args []string
+	switch format {
+	case "pe":
+		args = []string{"osslsigncode", "verify", "-in", file}
+	case "macho":
+		args = []string{"codesign", "--verify", "--strict", file}
+	}
+
+	tool, err := exec.LookPath(args[0])
+	if err != nil {
+		return "signed, not validated", nil
+	}
+	out, err := exec.CommandContext(d.ctx, tool, args[1:]...).CombinedOutput()
+	if err != nil {
+		if d.verbose {
+			d.stdout.Write(out)
+		}
+		return "invalid", fmt.Errorf("Invalid code signature %s", err)
+	}
+	return "valid", nil
+}
+
+// readBinaryInfo reads the format, the targets and the code signature of the executable at path
+func readBinaryInfo(path string) (binaryInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return binaryInfo{}, err
+	}
+	defer f.Close()
+
+	magic := make([]byte, 4)
+	_, err = io.ReadFull(f, magic)
+	if err != nil {
+		return binaryInfo{}, fmt.Errorf("Unknown executable format")
+	}
+
+	switch {
+	case bytes.Equal(magic, []byte(elf.ELFMAG)):
+		return readELFInfo(f)
+	case bytes.HasPrefix(magic, []byte("MZ")):
+		return readPEInfo(f)
+	case bytes.Equal(magic, []byte{0xca, 0xfe, 0xba, 0xbe}):
+		return readFatInfo(f)
+	case bytes.Equal(magic, []byte{0xcf, 0xfa, 0xed, 0xfe}), bytes.Equal(magic, []byte{0xce, 0xfa, 0xed, 0xfe}):
+		return readMachOInfo(f)
+	}
+	return binaryInfo{}, fmt.Errorf("Unknown executable format")
+}
+
+// readELFInfo reads the ELF headers. The GOOS is freebsd for the FreeBSD ABI, linux otherwise
+func readELFInfo(r io.ReaderAt) (binaryInfo, error) {
+	f, err := elf.NewFile(r)
+	if err != nil {
+		return binaryInfo{}, fmt.Errorf("Invalid ELF executable %s", err)
+	}
+	goos := "linux"
+	if f.OSABI == elf.ELFOSABI_FREEBSD {
+		goos = "freebsd"
+	}
+	return binaryInfo{
+		format:  "elf",
+		targets: []string{goos + "/" + archName(elfArchs[f.Machine], f.Machine)},
+	}, nil
+}
+
+// readPEInfo reads the PE headers. The executable is signed if the security directory is not empty
+func readPEInfo(r io.ReaderAt) (binaryInfo, error) {
+	f, err := pe.NewFile(r)
+	if err != nil {
+		return binaryInfo{}, fmt.Errorf("Invalid PE executable %s", err)
+	}
+	var security pe.DataDirectory
+	switch h := f.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		if h.NumberOfRvaAndSizes > pe.IMAGE_DIRECTORY_ENTRY_SECURITY {
+			security = h.DataDirectory[pe.IMAGE_DIRECTORY_ENTRY_SECURITY]
+		}
+	case *pe.OptionalHeader64:
+		if h.NumberOfRvaAndSizes > pe.IMAGE_DIRECTORY_ENTRY_SECURITY {
+			security = h.DataDirectory[pe.IMAGE_DIRECTORY_ENTRY_SECURITY]
+		}
+	}
+	return binaryInfo{
+		format:  "pe",
+		targets: []string{"windows/" + archName(peArchs[f.Machine], f.Machine)},
+		signed:  security.VirtualAddress != 0 && security.Size != 0,
+	}, nil
+}
+
+// readMachOInfo reads the Mach-O headers. The executable is signed if it has the code signature load command
+func readMachOInfo(r io.ReaderAt) (binaryInfo, error) {
+	f, err := macho.NewFile(r)
+	if err != nil {
+		return binaryInfo{}, fmt.Errorf("Invalid Mach-O executable %s", err)
+	}
+	return machOInfo(f), nil
+}
+
+// readFatInfo reads the headers of the macOS universal binary. The binary is signed if all its executables are
+func readFatInfo(r io.ReaderAt) (binaryInfo, error) {
+	ff, err := macho.NewFatFile(r)
+	if err != nil {
+		return binaryInfo{}, fmt.Errorf("Invalid Mach-O universal binary %s", err)
+	}
+	info := binaryInfo{format: "macho", signed: true}
+	for _, arch := range ff.Arches {
+		i := machOInfo(arch.File)
+		info.targets = append(info.targets, i.targets...)
+		info.signed = info.signed && i.signed
+	}
+	return info, nil
+}
+
+// machOInfo returns the info of the Mach-O executable f
+func machOInfo(f *macho.File) binaryInfo {
+	info := binaryInfo{
+		format:  "macho",
+		targets: []string{"darwin/" + archName(machoArchs[f.Cpu], f.Cpu)},
+	}
+	for _, l := range f.Loads {
+		raw := l.Raw()
+		if len(raw) >= 4 && f.ByteOrder.Uint32(raw) == lcCodeSignature {
+			info.signed = true
+		}
+	}
+	return info
+}
+
+// archName returns the GOARCH, or the machine type of the header if it has no GOARCH
+func archName(goarch string, machine interface{}) string {
+	if goarch != "" {
+		return goarch
+	}
+	return fmt.Sprint(machine)
+}
+
+// printVerifySummary prints the verification result of each artifact
+func printVerifySummary(w io.Writer, results []verifyResult) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "FILE\tTARGET\tSIGNATURE\tSTATUS")
+	for _, r := range results {
+		target := r.target
+		if target == "" {
+			target = "-"
+		}
+		status := "ok"
+		if r.err != nil {
+			status = r.err.Error()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.file, target, r.signature, status)
+	}
+	tw.Flush()
+}
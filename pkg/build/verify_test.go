@@ -0,0 +1,112 @@
+package build
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func Test_readBinaryInfo(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fyne-cross")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	text := filepath.Join(dir, "README.md")
+	err = ioutil.WriteFile(text, []byte("# fyne"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the test executable is built for the host
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	formats := map[string]string{"linux": "elf", "windows": "pe", "darwin": "macho"}
+
+	got, err := readBinaryInfo(exe)
+	if format, ok := formats[runtime.GOOS]; ok {
+		if err != nil {
+			t.Fatalf("readBinaryInfo() error = %v", err)
+		}
+		if got.format != format || !got.hasTarget(runtime.GOOS+"/"+runtime.GOARCH) {
+			t.Errorf("readBinaryInfo() = %v, want %s executable for %s/%s", got, format, runtime.GOOS, runtime.GOARCH)
+		}
+	}
+
+	_, err = readBinaryInfo(text)
+	if err == nil {
+		t.Errorf("readBinaryInfo() expected error for a text file")
+	}
+}
+
+func TestBuilder_Verify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fyne-cross")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	buildDir := filepath.Join(dir, "build")
+	os.MkdirAll(buildDir, 0755)
+	err = ioutil.WriteFile(filepath.Join(buildDir, "NOTES.txt"), []byte("fyne"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notes, err := newArtifact("", filepath.Join(buildDir, "NOTES.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := &Builder{workDir: dir}
+	tests := []struct {
+		name      string
+		artifacts []Artifact
+		wantErr   bool
+	}{
+		{
+			name:      "valid checksum",
+			artifacts: []Artifact{notes},
+		},
+		{
+			name:      "checksum mismatch",
+			artifacts: []Artifact{{File: "NOTES.txt", Size: 4, SHA256: "0000"}},
+			wantErr:   true,
+		},
+		{
+			name:      "missing artifact",
+			artifacts: []Artifact{notes, {File: "missing.txt"}},
+			wantErr:   true,
+		},
+		{
+			name:      "not an executable",
+			artifacts: []Artifact{{Target: "linux/amd64", Package: ".", File: notes.File, Size: notes.Size, SHA256: notes.SHA256}},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			d.stdout = &out
+			b, err := json.Marshal(manifest{Artifacts: tt.artifacts})
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = ioutil.WriteFile(filepath.Join(buildDir, manifestFile), b, 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = d.Verify(context.Background(), VerifyOptions{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Builder.Verify() error = %v, wantErr %v\n%s", err, tt.wantErr, out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package build
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// volumeMount represents an additional host folder or file mounted into the container
+type volumeMount struct {
+	hostPath      string
+	containerPath string
+	readOnly      bool
+}
+
+// parseVolume parses a volume in the form host:container[:ro]. The host path is resolved
+// to an absolute path and must exist, the container path must be absolute.
+// The container path is the last colon separated field, so that the Windows host paths
+// with a drive letter are supported
+func parseVolume(v string) (volumeMount, error) {
+	m := volumeMount{}
+	s := v
+	switch {
+	case strings.HasSuffix(s, ":ro"):
+		m.readOnly = true
+		s = strings.TrimSuffix(s, ":ro")
+	case strings.HasSuffix(s, ":rw"):
+		s = strings.TrimSuffix(s, ":rw")
+	}
+
+	i := strings.LastIndex(s, ":")
+	if i <= 0 || i == len(s)-1 {
+		return m, fmt.Errorf("Invalid volume %q, expected host:container[:ro]", v)
+	}
+	m.containerPath = s[i+1:]
+	if !path.IsAbs(m.containerPath) {
+		return m, fmt.Errorf("Invalid volume %q, the container path must be absolute", v)
+	}
+	m.containerPath = path.Clean(m.containerPath)
+
+	abs, err := filepath.Abs(s[:i])
+	if err != nil {
+		return m, err
+	}
+	_, err = os.Stat(abs)
+	if err != nil {
+		return m, fmt.Errorf("Cannot find the volume host path %s", err)
+	}
+	m.hostPath = abs
+	return m, nil
+}
+
+// reservedContainerPaths are the container folders mounted by fyne-cross. The additional volumes
+// cannot be mounted on or under them: the cache, also containing the module cache and the
+// GOPATH app dir, the app dir, the previous release folder and the custom CA certificates
+var reservedContainerPaths = map[string]string{
+	"/go":         "the cache",
+	defaultAppDir: "the package root directory",
+	deltaFromDir:  "the previous release",
+	caCertsDir:    "the CA certificates",
+}
+
+// resolveVolumes parses the volumes, see parseVolume. The volumes cannot be mounted
+// on or under the reserved container paths, see reservedContainerPaths
+func resolveVolumes(volumes []string) ([]volumeMount, error) {
+	mounts := []volumeMount{}
+	for _, v := range volumes {
+		m, err := parseVolume(v)
+		if err != nil {
+			return nil, err
+		}
+		for dir, usage := range reservedContainerPaths {
+			if m.containerPath == dir || strings.HasPrefix(m.containerPath, dir+"/") {
+				return nil, fmt.Errorf("Invalid volume %q, the container path %s is reserved to %s", v, dir, usage)
+			}
+		}
+		mounts = append(mounts, m)
+	}
+	return mounts, nil
+}
+
+// volumesArgs returns the arguments used to mount the additional volumes into the container
+func (d *Builder) volumesArgs() []string {
+	args := []string{}
+	for _, m := range d.volumes {
+		opts := []string{}
+		if m.readOnly {
+			opts = append(opts, "ro")
+		}
+		args = append(args, "-v", d.volume(m.hostPath, m.containerPath, opts...))
+	}
+	return args
+}
@@ -0,0 +1,145 @@
+package build
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_parseVolume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fyne-cross-volume")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name    string
+		volume  string
+		want    volumeMount
+		wantErr bool
+	}{
+		{
+			name:   "read-write",
+			volume: dir + ":/shared",
+			want:   volumeMount{hostPath: dir, containerPath: "/shared"},
+		},
+		{
+			name:   "read-only",
+			volume: dir + ":/shared/assets/:ro",
+			want:   volumeMount{hostPath: dir, containerPath: "/shared/assets", readOnly: true},
+		},
+		{
+			name:   "explicit read-write",
+			volume: dir + ":/shared:rw",
+			want:   volumeMount{hostPath: dir, containerPath: "/shared"},
+		},
+		{
+			name:    "missing container path",
+			volume:  dir,
+			wantErr: true,
+		},
+		{
+			name:    "relative container path",
+			volume:  dir + ":shared",
+			wantErr: true,
+		},
+		{
+			name:    "missing host path",
+			volume:  filepath.Join(dir, "missing") + ":/shared",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseVolume(tt.volume)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseVolume() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseVolume() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_resolveVolumes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fyne-cross-volume")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name    string
+		volume  string
+		wantErr bool
+	}{
+		{name: "shared folder", volume: dir + ":/shared"},
+		{name: "similar prefix", volume: dir + ":/gopath"},
+		{name: "cache", volume: dir + ":/go", wantErr: true},
+		{name: "module cache", volume: dir + ":/go/pkg/mod", wantErr: true},
+		{name: "GOPATH app dir", volume: dir + ":/go/src/github.com/fyne-io/example", wantErr: true},
+		{name: "app dir", volume: dir + ":/app", wantErr: true},
+		{name: "under the app dir", volume: dir + ":/app/assets:ro", wantErr: true},
+		{name: "previous release", volume: dir + ":/fyne-cross/delta-from", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := resolveVolumes([]string{tt.volume})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("resolveVolumes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuilder_volumesArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		volumes []volumeMount
+		selinux string
+		want    []string
+	}{
+		{
+			name:    "no volumes",
+			volumes: nil,
+			want:    []string{},
+		},
+		{
+			name: "volumes",
+			volumes: []volumeMount{
+				{hostPath: "/home/fyne/shared", containerPath: "/shared"},
+				{hostPath: "/home/fyne/assets", containerPath: "/assets", readOnly: true},
+			},
+			want: []string{
+				"-v", "/home/fyne/shared:/shared",
+				"-v", "/home/fyne/assets:/assets:ro",
+			},
+		},
+		{
+			name: "volumes with selinux label",
+			volumes: []volumeMount{
+				{hostPath: "/home/fyne/assets", containerPath: "/assets", readOnly: true},
+			},
+			selinux: "z",
+			want: []string{
+				"-v", "/home/fyne/assets:/assets:ro,z",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Builder{
+				volumes: tt.volumes,
+				selinux: tt.selinux,
+			}
+			if got := d.volumesArgs(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Builder.volumesArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package build
+
+import (
+	"fmt"
+	gobuild "go/build"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// defaultAppDir is the container path where the work dir is mounted
+const defaultAppDir = "/app"
+
+// resolveWorkDir validates the work dir and returns its absolute path
+// with the symbolic links evaluated
+func resolveWorkDir(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	abs, err = filepath.EvalSymlinks(abs)
+	if err != nil {
+		return "", fmt.Errorf("Cannot resolve the package root directory %s", err)
+	}
+
+	info, err := os.Stat(abs)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("The package root directory %q is not a directory", abs)
+	}
+
+	// the colon is the volume mount separator
+	if runtime.GOOS != "windows" && strings.Contains(abs, ":") {
+		return "", fmt.Errorf("The package root directory %q cannot contain a colon", abs)
+	}
+	return abs, nil
+}
+
+// gopathImportPath returns the import path of dir if it is located under
+// a GOPATH src directory, otherwise an empty string
+func gopathImportPath(dir string) string {
+	for _, gopath := range filepath.SplitList(gobuild.Default.GOPATH) {
+		src, err := filepath.EvalSymlinks(filepath.Join(gopath, "src"))
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(src, dir)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		return filepath.ToSlash(rel)
+	}
+	return ""
+}
+
+// hasGoMod returns true if dir contains a go.mod file
+func hasGoMod(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, "go.mod"))
+	return err == nil
+}
+
+// appDir returns the container path where the work dir is mounted
+func (d *Builder) appDir() string {
+	if d.importPath != "" && !d.gomod {
+		return "/go/src/" + d.importPath
+	}
+	return defaultAppDir
+}
+
+// moduleEnv returns the env variable used to set the module mode according to the project layout:
+// module mode when a go.mod file exists, GOPATH mode for projects located under the GOPATH
+func (d *Builder) moduleEnv() string {
+	if d.gomod {
+		return "GO111MODULE=on"
+	}
+	if d.importPath != "" {
+		return "GO111MODULE=off"
+	}
+	return ""
+}
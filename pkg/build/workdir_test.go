@@ -0,0 +1,94 @@
+package build
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_resolveWorkDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fyne-cross")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	dir, _ = filepath.EvalSymlinks(dir)
+
+	project := filepath.Join(dir, "my project")
+	os.Mkdir(project, 0755)
+	link := filepath.Join(dir, "link")
+	os.Symlink(project, link)
+	file := filepath.Join(dir, "main.go")
+	ioutil.WriteFile(file, []byte("package main"), 0644)
+
+	tests := []struct {
+		name    string
+		dir     string
+		want    string
+		wantErr bool
+	}{
+		{name: "dir with spaces", dir: project, want: project},
+		{name: "symlink", dir: link, want: project},
+		{name: "not a dir", dir: file, wantErr: true},
+		{name: "not exists", dir: filepath.Join(dir, "missing"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveWorkDir(tt.dir)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("resolveWorkDir() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("resolveWorkDir() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuilder_appDir(t *testing.T) {
+	type fields struct {
+		gomod      bool
+		importPath string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		wantDir string
+		wantEnv string
+	}{
+		{
+			name:    "module",
+			fields:  fields{gomod: true, importPath: "github.com/fyne-io/fyne-example"},
+			wantDir: "/app",
+			wantEnv: "GO111MODULE=on",
+		},
+		{
+			name:    "GOPATH",
+			fields:  fields{importPath: "github.com/fyne-io/fyne-example"},
+			wantDir: "/go/src/github.com/fyne-io/fyne-example",
+			wantEnv: "GO111MODULE=off",
+		},
+		{
+			name:    "outside GOPATH without go.mod",
+			fields:  fields{},
+			wantDir: "/app",
+			wantEnv: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Builder{
+				gomod:      tt.fields.gomod,
+				importPath: tt.fields.importPath,
+			}
+			if got := d.appDir(); got != tt.wantDir {
+				t.Errorf("Builder.appDir() = %v, want %v", got, tt.wantDir)
+			}
+			if got := d.moduleEnv(); got != tt.wantEnv {
+				t.Errorf("Builder.moduleEnv() = %v, want %v", got, tt.wantEnv)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/lucor/fyne-cross/pkg/build"
+)
+
+var (
+	// publishTag represents the release tag
+	publishTag string
+	// publishRepo represents the repository of the release
+	publishRepo string
+	// publishAPIURL represents the API URL of the release provider
+	publishAPIURL string
+)
+
+// publisher is the command publishing the built artifacts to a release
+type publisher struct{}
+
+func (p *publisher) addFlags() {
+	flag.StringVar(&pkgRootDir, "dir", "", "The package root directory. Default current dir")
+	flag.StringVar(&publishTag, "tag", "", "The release tag. Default to the CI tag env variable, GITHUB_REF_NAME or CI_COMMIT_TAG")
+	flag.StringVar(&publishRepo, "repo", "", "The repository as owner/name for GitHub or the project ID or path for GitLab. Default to the CI env variable, GITHUB_REPOSITORY or CI_PROJECT_ID")
+	flag.StringVar(&publishAPIURL, "api-url", "", "The API URL. Default to the CI env variable or the public instance API")
+	flag.BoolVar(&verbose, "v", false, "Enable verbosity. Default to false")
+}
+
+func (p *publisher) printHelp(indent string) {
+	fmt.Println("Usage: fyne-cross publish [parameters] provider|destination")
+	fmt.Println()
+	fmt.Println("Publish the artifacts of the build manifest to a release, the release is created if it does not exist")
+	fmt.Println()
+
+	fmt.Println("Providers:")
+	fmt.Println(indent, "- ", "github: uploads the artifacts as release assets. The token is read from GITHUB_TOKEN")
+	fmt.Println(indent, "- ", "gitlab: uploads the artifacts to the generic package registry and links them to the release. The token is read from GITLAB_TOKEN or CI_JOB_TOKEN")
+	fmt.Println()
+
+	fmt.Println("Destinations:")
+	fmt.Println(indent, "- ", "s3://bucket/prefix?region=<region>&endpoint=<url>: the credentials are read from AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	fmt.Println(indent, "- ", "sftp://user@host:port/dir: requires the OpenSSH sftp command, the authentication is handled by ssh")
+	fmt.Println(indent, "- ", "webdav://host/dir or webdavs://host/dir: the credentials are read from WEBDAV_USERNAME and WEBDAV_PASSWORD")
+	fmt.Println()
+
+	fmt.Println("Optional parameters:")
+	flag.PrintDefaults()
+	fmt.Println()
+
+	fmt.Println("Example: GITHUB_TOKEN=xxx fyne-cross publish --tag=v1.0.0 --repo=fyne-io/fyne github")
+	fmt.Println("Example: fyne-cross publish s3://releases/myapp/v1.0.0?region=eu-west-1")
+}
+
+func (p *publisher) run(args []string) {
+	if len(args) != 1 {
+		printUsage()
+		os.Exit(2)
+	}
+
+	var pub build.Publisher
+	var err error
+	switch args[0] {
+	case "github":
+		pub = &build.GitHubPublisher{Repo: publishRepo, Tag: publishTag, APIURL: publishAPIURL}
+	case "gitlab":
+		pub = &build.GitLabPublisher{Project: publishRepo, Tag: publishTag, APIURL: publishAPIURL}
+	default:
+		pub, err = build.NewUploadPublisher(args[0])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	db, err := build.NewBuilder(build.Options{
+		Dir:     pkgRootDir,
+		Verbose: verbose,
+	})
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+	err = db.Publish(ctx, pub)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lucor/fyne-cross/pkg/build"
+)
+
+// runner is the command running the linux build of the application into a docker container
+type runner struct{}
+
+func (r *runner) addFlags() {
+	flag.StringVar(&targetList, "target", "linux/amd64", "The linux target to run")
+	flag.StringVar(&output, "output", "", "The named output file. Default to package name")
+	flag.StringVar(&pkgRootDir, "dir", "", "The package root directory. Default current dir")
+	flag.BoolVar(&targetSubdir, "target-subdir", false, "The artifacts are into the build/<goos>-<goarch> subdirectory of each target. Default to false")
+	flag.StringVar(&selinuxLabel, "selinux-label", "auto", "The SELinux label option for the volume mounts: z (shared), Z (private) or none. Default to z when SELinux is enforcing on the host")
+	flag.StringVar(&image, "image", build.DefaultImage, "The docker image used to run the application")
+	flag.Var(&aptPackages, "apt-package", "An extra system package to install into the image, i.e. libvlc5. Can be repeated")
+	flag.BoolVar(&verbose, "v", false, "Enable verbosity. Default to false")
+	addEnvFlags()
+	addUserFlags()
+}
+
+func (r *runner) printHelp(indent string) {
+	fmt.Println("Usage: fyne-cross run [parameters] [package] [-- arguments]")
+	fmt.Println()
+	fmt.Println("Run the linux build of a Fyne application into the fyne-cross docker container")
+	fmt.Println()
+
+	fmt.Println("Package is the package used to build the application. Default to '.'")
+	fmt.Println("Arguments are passed to the application")
+	fmt.Println("The X11 and Wayland sockets and the GPU devices of the host are forwarded to the container")
+	fmt.Println()
+
+	fmt.Println("Optional parameters:")
+	flag.PrintDefaults()
+	fmt.Println()
+
+	fmt.Println("Example: fyne-cross run --target=linux/amd64 ./cmd/test -- --debug")
+}
+
+func (r *runner) run(args []string) {
+	pkg, appArgs, err := runArgs(args)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	db, err := newBuilder([]string{pkg})
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+	err = db.Run(ctx, appArgs)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// keepSeparator returns the arguments left after the flags parsing, with the "--" separator the
+// flag package consumes when it terminates the flags, i.e. fyne-cross run --target=linux/amd64 -- --debug
+func keepSeparator(args []string, parsed []string) []string {
+	n := len(args) - len(parsed)
+	if n > 0 && args[n-1] == "--" {
+		return append([]string{"--"}, parsed...)
+	}
+	return parsed
+}
+
+// runArgs returns the package, default to ".", and the application arguments of the run command.
+// The application arguments are the ones following the first "--" separator, if any, otherwise
+// the ones following the package
+func runArgs(args []string) (string, []string, error) {
+	for i, arg := range args {
+		if arg != "--" {
+			continue
+		}
+		if i > 1 {
+			return "", nil, fmt.Errorf("Only one package can be run, got %s", strings.Join(args[:i], " "))
+		}
+		pkg := "."
+		if i == 1 {
+			pkg = args[0]
+		}
+		return pkg, args[i+1:], nil
+	}
+
+	if len(args) == 0 {
+		return ".", []string{}, nil
+	}
+	return args[0], args[1:], nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func Test_runArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantPkg     string
+		wantAppArgs []string
+		wantErr     bool
+	}{
+		{
+			name:        "default package",
+			args:        []string{},
+			wantPkg:     ".",
+			wantAppArgs: []string{},
+		},
+		{
+			name:        "package with separator",
+			args:        []string{"./cmd/app", "--", "--debug"},
+			wantPkg:     "./cmd/app",
+			wantAppArgs: []string{"--debug"},
+		},
+		{
+			name:        "separator without package",
+			args:        []string{"--", "--debug"},
+			wantPkg:     ".",
+			wantAppArgs: []string{"--debug"},
+		},
+		{
+			name:        "separator passed to the application",
+			args:        []string{"./cmd/app", "--", "--debug", "--", "file"},
+			wantPkg:     "./cmd/app",
+			wantAppArgs: []string{"--debug", "--", "file"},
+		},
+		{
+			name:        "package without separator",
+			args:        []string{"./cmd/app", "file"},
+			wantPkg:     "./cmd/app",
+			wantAppArgs: []string{"file"},
+		},
+		{
+			name:    "more packages",
+			args:    []string{"./cmd/app", "./cmd/other", "--", "--debug"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg, appArgs, err := runArgs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("runArgs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if pkg != tt.wantPkg {
+				t.Errorf("runArgs() pkg = %v, want %v", pkg, tt.wantPkg)
+			}
+			if !reflect.DeepEqual(appArgs, tt.wantAppArgs) {
+				t.Errorf("runArgs() appArgs = %v, want %v", appArgs, tt.wantAppArgs)
+			}
+		})
+	}
+}
+
+func Test_keepSeparator(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "separator after the package",
+			args: []string{"--target=linux/amd64", "./cmd/app", "--", "--debug"},
+			want: []string{"./cmd/app", "--", "--debug"},
+		},
+		{
+			name: "separator terminating the flags",
+			args: []string{"--target=linux/amd64", "--", "--debug"},
+			want: []string{"--", "--debug"},
+		},
+		{
+			name: "no separator",
+			args: []string{"--target=linux/amd64", "./cmd/app"},
+			want: []string{"./cmd/app"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("run", flag.ContinueOnError)
+			fs.SetOutput(ioutil.Discard)
+			fs.String("target", "", "")
+			err := fs.Parse(tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := keepSeparator(tt.args, fs.Args())
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("keepSeparator() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
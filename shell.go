@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/lucor/fyne-cross/pkg/build"
+)
+
+// sheller is the command opening an interactive shell into the docker container
+type sheller struct{}
+
+func (s *sheller) addFlags() {
+	defaultTarget := build.DefaultTarget()
+	flag.StringVar(&targetList, "target", defaultTarget, fmt.Sprintf("The target to set the env for. Default to current GOOS/GOARCH %s", defaultTarget))
+	addCommonFlags()
+}
+
+func (s *sheller) printHelp(indent string) {
+	fmt.Println("Usage: fyne-cross shell [parameters]")
+	fmt.Println()
+	fmt.Println("Open an interactive shell into the fyne-cross docker container")
+	fmt.Println()
+
+	fmt.Println("The shell has the same mounts and env used to build the target,")
+	fmt.Println("i.e. to debug CGO and linker issues")
+	fmt.Println()
+
+	fmt.Println("Optional parameters:")
+	flag.PrintDefaults()
+	fmt.Println()
+
+	fmt.Println("Example: fyne-cross shell --target=windows/amd64")
+}
+
+func (s *sheller) run(args []string) {
+	db, err := newBuilder([]string{"."})
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	err = db.Shell(context.Background())
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/lucor/fyne-cross/pkg/build"
+)
+
+// targetsJSON represents the setting to print the targets as JSON
+var targetsJSON bool
+
+// targetInfo describes a supported target and its default build settings
+type targetInfo struct {
+	Target  string   `json:"target"`
+	GOOS    string   `json:"goos"`
+	GOARCH  string   `json:"goarch"`
+	CC      string   `json:"cc"`
+	Env     []string `json:"env"`
+	Ldflags string   `json:"ldflags"`
+}
+
+// targetLister is the command listing the supported targets
+type targetLister struct{}
+
+func (l *targetLister) addFlags() {
+	flag.BoolVar(&targetsJSON, "json", false, "Print the targets as JSON. Default to false")
+}
+
+func (l *targetLister) printHelp(indent string) {
+	fmt.Println("Usage: fyne-cross targets [parameters]")
+	fmt.Println()
+	fmt.Println("List the supported targets with their default env and ldflags")
+	fmt.Println()
+
+	fmt.Println("Optional parameters:")
+	flag.PrintDefaults()
+	fmt.Println()
+
+	fmt.Println("Example: fyne-cross targets --json | jq -c '[.[].target]'")
+}
+
+func (l *targetLister) run(args []string) {
+	if len(args) > 0 {
+		printUsage()
+		os.Exit(2)
+	}
+
+	var err error
+	if targetsJSON {
+		err = printTargetsJSON(os.Stdout, describeTargets())
+	} else {
+		err = printTargets(os.Stdout, describeTargets())
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// describeTargets returns the supported targets sorted by name
+func describeTargets() []targetInfo {
+	infos := []targetInfo{}
+	for _, target := range build.SupportedTargets() {
+		info := targetInfo{
+			Target:  target,
+			Env:     build.DefaultEnv(target),
+			Ldflags: build.DefaultLdflags(target),
+		}
+		for _, env := range info.Env {
+			parts := strings.SplitN(env, "=", 2)
+			switch parts[0] {
+			case "GOOS":
+				info.GOOS = parts[1]
+			case "GOARCH":
+				info.GOARCH = parts[1]
+			case "CC":
+				info.CC = parts[1]
+			}
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// printTargets prints the targets as table
+func printTargets(w io.Writer, infos []targetInfo) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "TARGET\tGOOS\tGOARCH\tCC\tLDFLAGS")
+	for _, info := range infos {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", info.Target, info.GOOS, info.GOARCH, info.CC, info.Ldflags)
+	}
+	return tw.Flush()
+}
+
+// printTargetsJSON prints the targets as JSON array
+func printTargetsJSON(w io.Writer, infos []targetInfo) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(infos)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func Test_describeTargets(t *testing.T) {
+	var got targetInfo
+	for _, info := range describeTargets() {
+		if info.Target == "windows/amd64" {
+			got = info
+		}
+	}
+	want := targetInfo{
+		Target:  "windows/amd64",
+		GOOS:    "windows",
+		GOARCH:  "amd64",
+		CC:      "x86_64-w64-mingw32-gcc",
+		Env:     []string{"GOOS=windows", "GOARCH=amd64", "CC=x86_64-w64-mingw32-gcc"},
+		Ldflags: "-H windowsgui",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("describeTargets() = %v, want %v", got, want)
+	}
+}
+
+func Test_printTargetsJSON(t *testing.T) {
+	infos := describeTargets()
+
+	var buf bytes.Buffer
+	err := printTargetsJSON(&buf, infos)
+	if err != nil {
+		t.Fatalf("printTargetsJSON() error = %v", err)
+	}
+
+	got := []targetInfo{}
+	err = json.Unmarshal(buf.Bytes(), &got)
+	if err != nil {
+		t.Fatalf("printTargetsJSON() invalid JSON %v", err)
+	}
+	if !reflect.DeepEqual(got, infos) {
+		t.Errorf("printTargetsJSON() = %v, want %v", got, infos)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/lucor/fyne-cross/pkg/build"
+)
+
+var (
+	// testRun represents the regular expression used to select the tests to run
+	testRun string
+	// testCount represents the number of times to run each test
+	testCount int
+	// testCoverProfile represents the coverage profile file
+	testCoverProfile string
+)
+
+// tester is the command running the go tests into the docker container
+type tester struct{}
+
+func (t *tester) addFlags() {
+	addTargetsFlag("test")
+	addCommonFlags()
+	flag.StringVar(&testRun, "run", "", "Run only those tests matching the regular expression")
+	flag.IntVar(&testCount, "count", 0, "Run each test n times. Default to the go test default")
+	flag.StringVar(&testCoverProfile, "coverprofile", "", "Write a coverage profile for each target. The target is appended to the file name, i.e. cover-linux-amd64.out")
+}
+
+func (t *tester) printHelp(indent string) {
+	fmt.Println("Usage: fyne-cross test [parameters] [packages]")
+	fmt.Println()
+	fmt.Println("Run the go tests into the fyne-cross docker container for each target")
+	fmt.Println()
+
+	fmt.Println("Packages are the packages to test. Default to './...'")
+	fmt.Println("Tests are executed only for the linux targets, for the other targets they are compiled only")
+	fmt.Println()
+
+	fmt.Println("Optional parameters:")
+	flag.PrintDefaults()
+	fmt.Println()
+
+	fmt.Println("Example: fyne-cross test --targets=linux/amd64,windows/amd64 -run TestApp ./...")
+}
+
+func (t *tester) run(args []string) {
+	db, err := newBuilder([]string{"."})
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	opts := build.TestOptions{
+		Run:          testRun,
+		Count:        testCount,
+		CoverProfile: testCoverProfile,
+	}
+	ctx, cancel := signalContext()
+	defer cancel()
+	err = db.Test(ctx, args, opts)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/lucor/fyne-cross/pkg/build"
+)
+
+var (
+	// verifyRequireSignature represents the setting to fail on the unsigned windows and darwin executables
+	verifyRequireSignature bool
+)
+
+// verifier is the command verifying the integrity of the built artifacts
+type verifier struct{}
+
+func (v *verifier) addFlags() {
+	flag.StringVar(&pkgRootDir, "dir", "", "The package root directory. Default current dir")
+	flag.BoolVar(&verifyRequireSignature, "require-signature", false, "Fail if the windows and darwin executables are not signed. Default to false")
+	flag.BoolVar(&verbose, "v", false, "Enable verbosity. Default to false")
+}
+
+func (v *verifier) printHelp(indent string) {
+	fmt.Println("Usage: fyne-cross verify [parameters]")
+	fmt.Println()
+	fmt.Println("Verify the artifacts of the build manifest: the checksums are recomputed and the GOOS/GOARCH")
+	fmt.Println("of the executables is read from the file headers.")
+	fmt.Println("The code signatures are validated via osslsigncode for windows and codesign for darwin, when found in PATH")
+	fmt.Println()
+
+	fmt.Println("Optional parameters:")
+	flag.PrintDefaults()
+	fmt.Println()
+
+	fmt.Println("Example: fyne-cross verify --require-signature")
+}
+
+func (v *verifier) run(args []string) {
+	if len(args) != 0 {
+		printUsage()
+		os.Exit(2)
+	}
+
+	db, err := build.NewBuilder(build.Options{
+		Dir:     pkgRootDir,
+		Verbose: verbose,
+	})
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+	err = db.Verify(ctx, build.VerifyOptions{RequireSignature: verifyRequireSignature})
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}